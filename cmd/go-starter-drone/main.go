@@ -12,14 +12,12 @@ written permission of Adobe.
 package main
 
 import (
-	"context"
 	"flag"
 	"fmt"
+	"github.com/adobe/go-starter/pkg/auth"
 	"github.com/adobe/go-starter/pkg/console"
 	"github.com/adobe/go-starter/pkg/keychainx"
 	"github.com/drone/drone-go/drone"
-	"golang.org/x/oauth2"
-	"io/ioutil"
 	"net/url"
 	"os"
 	"os/exec"
@@ -34,6 +32,10 @@ var (
 	fileSecretsPull    SliceFlag
 	literalSecrets     SliceFlag
 	literalSecretsPull SliceFlag
+	vaultSecrets       SliceFlag
+	vaultSecretsPull   SliceFlag
+	tokenFile          string
+	tokenVault         string
 )
 
 func usage() {
@@ -54,6 +56,10 @@ func main() {
 	flag.Var(&fileSecretsPull, "pull-secret-file", "Create a secret from file available for pull-requests (eq. --pull-secret-file=secret_name=./path/to/file)")
 	flag.Var(&literalSecrets, "secret-literal", "Create a secret from literal (eq. --secret-literal=secret_name=value)")
 	flag.Var(&literalSecretsPull, "pull-secret-literal", "Create a secret from literal available for pull-requests (eq. --pull-secret-literal=secret_name=value)")
+	flag.Var(&vaultSecrets, "secret-vault", "Create a secret from HashiCorp Vault KV secret (eq. --secret-vault=secret_name=secret/path#field)")
+	flag.Var(&vaultSecretsPull, "pull-secret-vault", "Create a secret from HashiCorp Vault KV secret available for pull-requests (eq. --pull-secret-vault=secret_name=secret/path#field)")
+	flag.StringVar(&tokenFile, "token-file", "", "Read Drone personal token from a file")
+	flag.StringVar(&tokenVault, "token-vault", "", "Read Drone personal token from HashiCorp Vault KV secret (eq. --token-vault=secret/path#field), Vault is configured with VAULT_ADDR and VAULT_TOKEN environment variables")
 	flag.Parse()
 
 	ui := console.New(os.Stdin, os.Stdout)
@@ -80,26 +86,28 @@ func main() {
 		ui.Fatalf("Repository name is empty\n")
 	}
 
-	// get credentials from OSX keychain
-	_, pass, err := keychainx.Load(uri.Host)
-	if err != nil && err != keychainx.ErrNotFound {
-		ui.Fatalf("An error occurred while reading Drone token from keychain: %v\n", err)
+	// get token from environment, token file, Vault or keychain
+	credentials := auth.TokenChain{
+		UI:      ui,
+		Service: "Drone",
+		Env:     "DRONE_TOKEN",
+		File:    tokenFile,
+		Vault:   tokenVault,
+		Verify: func(user, pass string) error {
+			return auth.VerifyDrone(uri, pass)
+		},
+		Ask: func() (string, string) {
+			return "drone", auth.AskDrone(ui, uri)
+		},
 	}
 
-	if err == keychainx.ErrNotFound {
-		pass = AskCredentials(ui, uri)
-
-		if err := keychainx.Save(uri.Host, "drone", pass); err != nil {
-			ui.Errorf("An error occurred while writing Drone token to keychain: %v\n", err)
-		}
+	_, pass, err := credentials.Credentials(uri.Host)
+	if err != nil {
+		ui.Fatalf("An error occurred while reading Drone token: %v\n", err)
 	}
 
-	// create an http client with oauth authentication
-	auth := new(oauth2.Config).Client(context.Background(), &oauth2.Token{AccessToken: pass})
-	auth.Timeout = 30 * time.Second
-
 	// create the drone client with authenticator
-	dcli := drone.NewClient(uri.String(), auth)
+	dcli := auth.NewDroneClient(uri, pass)
 
 	ui.Printf("Sync repository list in drone\n")
 	if _, err := dcli.RepoListSync(); err != nil {
@@ -132,53 +140,29 @@ func main() {
 }
 
 func ImportSecrets(ui *console.Console, dcli drone.Client, org string, repo string) {
-	if len(fileSecrets)+len(fileSecretsPull)+len(literalSecrets)+len(literalSecretsPull) == 0 {
+	if len(fileSecrets)+len(fileSecretsPull)+len(literalSecrets)+len(literalSecretsPull)+len(vaultSecrets)+len(vaultSecretsPull) == 0 {
 		return
 	}
 
 	ui.Titlef("Importing repository secrets...\n")
 
-	for _, secret := range literalSecretsPull {
-		key, value := SplitKeyValue(secret)
-
-		ui.Printf("Adding secret %#v from literal...\n", key)
-		if err := CreateOrUpdateSecret(dcli, org, repo, key, value, true); err != nil {
-			ui.Errorf("An error occurred while adding secret: %v\n", err)
+	for _, pulls := range []bool{true, false} {
+		literals, files, vault := literalSecrets, fileSecrets, vaultSecrets
+		if pulls {
+			literals, files, vault = literalSecretsPull, fileSecretsPull, vaultSecretsPull
 		}
-	}
 
-	for _, secret := range literalSecrets {
-		key, value := SplitKeyValue(secret)
-
-		ui.Printf("Adding secret %#v from literal...\n", key)
-		if err := CreateOrUpdateSecret(dcli, org, repo, key, value, false); err != nil {
-			ui.Errorf("An error occurred while adding secret: %v\n", err)
-		}
-	}
-
-	for _, secret := range fileSecretsPull {
-		key, file := SplitKeyValue(secret)
-		value, err := ioutil.ReadFile(file)
+		secrets, err := keychainx.ReadSecrets(literals, files, vault)
 		if err != nil {
-			ui.Errorf("An error occurred while reading secret file %#v: %v\n", file, err)
+			ui.Errorf("An error occurred while reading secrets: %v\n", err)
+			continue
 		}
 
-		ui.Printf("Adding secret %#v from file...\n", key)
-		if err := CreateOrUpdateSecret(dcli, org, repo, key, string(value), true); err != nil {
-			ui.Errorf("An error occurred while adding secret: %v\n", err)
-		}
-	}
-
-	for _, secret := range fileSecrets {
-		key, file := SplitKeyValue(secret)
-		value, err := ioutil.ReadFile(file)
-		if err != nil {
-			ui.Errorf("An error occurred while reading secret file %#v: %v\n", file, err)
-		}
-
-		ui.Printf("Adding secret %#v from file...\n", key)
-		if err := CreateOrUpdateSecret(dcli, org, repo, key, string(value), false); err != nil {
-			ui.Errorf("An error occurred while adding secret: %v\n", err)
+		for _, secret := range secrets {
+			ui.Printf("Adding secret %#v...\n", secret.Name)
+			if err := CreateOrUpdateSecret(dcli, org, repo, secret.Name, secret.Value, pulls); err != nil {
+				ui.Errorf("An error occurred while adding secret: %v\n", err)
+			}
 		}
 	}
 }
@@ -210,40 +194,6 @@ func CreateOrUpdateSecret(dcli drone.Client, owner, repo, key string, value stri
 	return err
 }
 
-// SplitKeyValue for string in format key=value
-func SplitKeyValue(c string) (string, string) {
-	if parts := strings.SplitN(c, "=", 2); len(parts) == 2 {
-		return parts[0], parts[1]
-	}
-
-	return c, ""
-}
-
-// AskCredentials (token) for drone
-func AskCredentials(ui *console.Console, u *url.URL) (pass string) {
-	for {
-		ui.Printf("Follow this link to get your Personal Token: %v://%v/account.\n", u.Scheme, u.Host)
-
-		pass = ui.ReadString("Enter your personal token: ")
-
-		// build drone client
-		auth := new(oauth2.Config).Client(context.Background(), &oauth2.Token{AccessToken: pass})
-		auth.Timeout = 30 * time.Second
-
-		// create the drone client with authenticator
-		client := drone.NewClient(u.String(), auth)
-
-		// gets the current user
-		_, err := client.Self()
-		if err == nil {
-			return
-		}
-
-		ui.Errorf("An error occurred while validating your personal token: %v\n", err)
-		ui.Errorf("Credentials do not appear to be valid, try again...\n")
-	}
-}
-
 // run a cli command
 func run(name string, args ...string) error {
 	cmd := exec.Command(name, args...)
@@ -0,0 +1,58 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-github/github"
+	"net/http"
+)
+
+// Actions configuration of the repository
+type Actions struct {
+	Variables    map[string]string `yaml:"variables"`
+	Environments []string          `yaml:"environments"`
+}
+
+// SetVariable creates or updates GitHub Actions variable
+func SetVariable(cli *github.Client, owner, repo, name, value string) error {
+	ctx := context.Background()
+	body := map[string]string{"name": name, "value": value}
+
+	req, err := cli.NewRequest("PATCH", fmt.Sprintf("repos/%v/%v/actions/variables/%v", owner, repo, name), body)
+	if err != nil {
+		return err
+	}
+
+	_, err = cli.Do(ctx, req, nil)
+	if e, ok := err.(*github.ErrorResponse); ok && e.Response != nil && e.Response.StatusCode == http.StatusNotFound {
+		if req, err = cli.NewRequest("POST", fmt.Sprintf("repos/%v/%v/actions/variables", owner, repo), body); err != nil {
+			return err
+		}
+
+		_, err = cli.Do(ctx, req, nil)
+	}
+
+	return err
+}
+
+// CreateEnvironment creates deployment environment, existing environment is not changed
+func CreateEnvironment(cli *github.Client, owner, repo, name string) error {
+	req, err := cli.NewRequest("PUT", fmt.Sprintf("repos/%v/%v/environments/%v", owner, repo, name), map[string]interface{}{})
+	if err != nil {
+		return err
+	}
+
+	_, err = cli.Do(context.Background(), req, nil)
+	return err
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"github.com/adobe/go-starter/pkg/scm"
+	"golang.org/x/crypto/nacl/box"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestActions(t *testing.T) {
+	public, private, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := `{"key_id":"1234","key":"` + base64.StdEncoding.EncodeToString(public[:]) + `"}`
+
+	server, cli := newFakeGitHub(t, map[string]response{
+		"GET /api/v3/repos/adobe/awesome/actions/secrets/public-key": {body: key},
+		"PUT /api/v3/repos/adobe/awesome/actions/secrets/TOKEN":      {status: http.StatusCreated},
+		"POST /api/v3/repos/adobe/awesome/actions/variables":         {status: http.StatusCreated},
+		"PUT /api/v3/repos/adobe/awesome/environments/production":    {status: http.StatusCreated},
+	})
+
+	defer server.Close()
+
+	if err := scm.NewGitHub(cli).SetSecret("adobe", "awesome", "TOKEN", "secret"); err != nil {
+		t.Fatalf("SetSecret() failed: %v", err)
+	}
+
+	if err := SetVariable(cli, "adobe", "awesome", "GO_VERSION", "1.12"); err != nil {
+		t.Fatalf("SetVariable() failed: %v", err)
+	}
+
+	if err := CreateEnvironment(cli, "adobe", "awesome", "production"); err != nil {
+		t.Fatalf("CreateEnvironment() failed: %v", err)
+	}
+
+	secret, _ := server.requests["PUT /api/v3/repos/adobe/awesome/actions/secrets/TOKEN"].(map[string]interface{})
+	if secret["key_id"] != "1234" {
+		t.Errorf("Key ID does not match: got %#v, want %#v", secret["key_id"], "1234")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(fmt.Sprint(secret["encrypted_value"]))
+	if err != nil {
+		t.Fatalf("Encrypted value is not base64 encoded: %v", err)
+	}
+
+	if value, ok := box.OpenAnonymous(nil, sealed, public, private); !ok || string(value) != "secret" {
+		t.Errorf("Decrypted secret does not match: got %#v, want %#v", string(value), "secret")
+	}
+
+	want := map[string]interface{}{"name": "GO_VERSION", "value": "1.12"}
+	if v := server.requests["POST /api/v3/repos/adobe/awesome/actions/variables"]; !reflect.DeepEqual(v, want) {
+		t.Errorf("Variable does not match: got %#v, want %#v", v, want)
+	}
+
+	if _, ok := server.requests["PUT /api/v3/repos/adobe/awesome/environments/production"]; !ok {
+		t.Errorf("Environment is not created")
+	}
+}
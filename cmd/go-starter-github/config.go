@@ -0,0 +1,47 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+)
+
+// Config of the repository, loaded from a file passed with -config flag
+type Config struct {
+	Description string     `yaml:"description"`
+	Homepage    string     `yaml:"homepage"`
+	Topics      []string   `yaml:"topics"`
+	Merge       Merge      `yaml:"merge"`
+	Teams       []Team     `yaml:"teams"`
+	Labels      []Label    `yaml:"labels"`
+	Actions     Actions    `yaml:"actions"`
+	Webhooks    []Webhook  `yaml:"webhooks"`
+	Protection  Protection `yaml:"protection"`
+}
+
+// LoadConfig from YAML file
+func LoadConfig(path string) (Config, error) {
+	var config Config
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return config, fmt.Errorf("unable to parse %v: %v", path, err)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"github.com/adobe/go-starter/pkg/auth"
+	"github.com/adobe/go-starter/pkg/console"
+	"github.com/adobe/go-starter/pkg/keychainx"
+	"io/ioutil"
+)
+
+// Credentials configured with command line flags
+type Credentials struct {
+	TokenFile       string
+	TokenVault      string
+	AppID           int64
+	AppKey          string
+	AppInstallation int64
+}
+
+// Authenticate returns GitHub token and login of its user. GitHub App installation token is used when app is
+// configured (login is empty in this case), otherwise token is taken from environment, token file, Vault, git
+// credential helpers, keychain or asked from user.
+func Authenticate(ui *console.Console, host, org string, c Credentials, scopes []string) (string, string) {
+	if c.AppID != 0 {
+		key, err := ioutil.ReadFile(c.AppKey)
+		if err != nil {
+			ui.Fatalf("An error occurred while reading GitHub App private key: %v\n", err)
+		}
+
+		app := auth.GitHubApp{Host: host, ID: c.AppID, Key: key, Installation: c.AppInstallation, Owner: org}
+
+		token, err := app.Token()
+		if err != nil {
+			ui.Fatalf("An error occurred while authenticating as GitHub App: %v\n", err)
+		}
+
+		return token, ""
+	}
+
+	u, err := auth.GitHubURL(host)
+	if err != nil {
+		ui.Fatalf("%v\n", err)
+	}
+
+	credentials := auth.TokenChain{
+		UI:            ui,
+		Service:       "GitHub",
+		Env:           "GITHUB_TOKEN",
+		File:          c.TokenFile,
+		Vault:         c.TokenVault,
+		GitCredential: true,
+		// token stored in keychain could have not enough scopes, ask for new one in that case
+		Verify: func(user, pass string) error {
+			_, err := auth.VerifyGitHub(host, pass, scopes...)
+			if _, ok := err.(*auth.ScopesError); ok {
+				ui.Errorf("%v\n", err)
+				return keychainx.ErrRejected
+			}
+
+			return err
+		},
+		Ask: func() (string, string) {
+			return auth.AskGitHub(ui, host, scopes...)
+		},
+	}
+
+	// credentials are stored per host
+	_, token, err := credentials.Credentials(u.Host)
+	if err != nil {
+		ui.Fatalf("An error occurred while loading credentials: %v\n", err)
+	}
+
+	login, err := auth.VerifyGitHub(host, token, scopes...)
+	if err != nil {
+		ui.Fatalf("An error occurred while validating GitHub token: %v\n", err)
+	}
+
+	return token, login
+}
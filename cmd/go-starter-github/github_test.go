@@ -0,0 +1,87 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"github.com/adobe/go-starter/pkg/auth"
+	"github.com/google/go-github/github"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// response of the fake GitHub API, empty body is replied as {}
+type response struct {
+	status int
+	body   string
+	header map[string]string
+}
+
+// fakeGitHub replies with canned responses by "METHOD /path?query" (falling back to "METHOD /path")
+// and records bodies of requests other than GET by "METHOD /path"
+type fakeGitHub struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	responses map[string]response
+	requests  map[string]interface{}
+}
+
+func newFakeGitHub(t *testing.T, responses map[string]response) (*fakeGitHub, *github.Client) {
+	f := &fakeGitHub{responses: responses, requests: map[string]interface{}{}}
+
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		key := r.Method + " " + r.URL.Path
+
+		if r.Method != "GET" {
+			var body interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			f.requests[key] = body
+		}
+
+		resp, ok := f.responses[key+"?"+r.URL.RawQuery]
+		if !ok {
+			resp, ok = f.responses[key]
+		}
+
+		if !ok {
+			resp = response{status: http.StatusNotFound, body: `{"message":"Not Found"}`}
+		}
+
+		for k, v := range resp.header {
+			w.Header().Set(k, v)
+		}
+
+		if resp.status != 0 {
+			w.WriteHeader(resp.status)
+		}
+
+		if resp.body == "" {
+			resp.body = "{}"
+		}
+
+		_, _ = w.Write([]byte(resp.body))
+	}))
+
+	cli, err := auth.NewGitHubClient(f.URL, "token")
+	if err != nil {
+		f.Close()
+		t.Fatal(err)
+	}
+
+	return f, cli
+}
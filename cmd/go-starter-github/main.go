@@ -13,17 +13,20 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
 	"flag"
 	"fmt"
+	"github.com/adobe/go-starter/pkg/auth"
 	"github.com/adobe/go-starter/pkg/console"
 	"github.com/adobe/go-starter/pkg/keychainx"
+	"github.com/adobe/go-starter/pkg/scm"
 	"github.com/google/go-github/github"
 	"io/ioutil"
 	"net/http"
 	"os"
 	"os/exec"
+	"sort"
 	"strings"
-	"time"
 )
 
 var version, commit string
@@ -41,11 +44,16 @@ func usage() {
 }
 
 func main() {
-	var remote, branch, deployKey string
-	var public, issues, projects, wiki bool
-	var collaborators SliceFlag
+	var host, remote, branch, deployKey, config, template, fork string
+	var description, homepage string
+	var public, issues, projects, wiki, squashOnly, deleteBranch, reconcile, dryRun bool
+	var collaborators, teams, topics SliceFlag
+	var literalSecrets, fileSecrets, vaultSecrets, variables, environments, webhooks SliceFlag
+	var creds Credentials
+	var protection Protection
 
 	flag.Usage = usage
+	flag.StringVar(&host, "host", hostDefault(), "GitHub host, use it for GitHub Enterprise (eq. github.example.com). Can be set with GITHUB_HOST environment variable")
 	flag.StringVar(&remote, "remote", "upstream", "Name of the remote in local repository")
 	flag.StringVar(&branch, "branch", "master", "Name of the master branch")
 	flag.BoolVar(&issues, "with-issues", false, "Enable issues in GitHub")
@@ -53,6 +61,36 @@ func main() {
 	flag.BoolVar(&wiki, "with-wiki", false, "Enable wiki page in GitHub")
 	flag.StringVar(&deployKey, "deploy-key", "", "Add SSH deployment key to the repository, add ':rw' suffix to grant write permissions to the key")
 	flag.BoolVar(&public, "public", false, "Make repository public")
+	flag.StringVar(&creds.TokenFile, "token-file", "", "Read GitHub personal token from a file")
+	flag.StringVar(&creds.TokenVault, "token-vault", "", "Read GitHub personal token from HashiCorp Vault KV secret (eq. --token-vault=secret/path#field), Vault is configured with VAULT_ADDR and VAULT_TOKEN environment variables")
+	flag.Int64Var(&creds.AppID, "app-id", 0, "Authenticate as GitHub App with a given ID, requires -app-key")
+	flag.StringVar(&creds.AppKey, "app-key", "", "Path to the private key of GitHub App")
+	flag.Int64Var(&creds.AppInstallation, "app-installation", 0, "ID of GitHub App installation, by default installation is looked up by organisation")
+	flag.StringVar(&description, "description", "", "Description of the repository")
+	flag.StringVar(&homepage, "homepage", "", "URL of the project homepage")
+	flag.Var(&topics, "topic", "Add topic to the repository. Can be specified multiple times. Example: --topic go --topic starter")
+	flag.BoolVar(&squashOnly, "squash-only", false, "Allow only squash merging of pull requests")
+	flag.BoolVar(&deleteBranch, "delete-branch-on-merge", false, "Automatically delete head branches after pull requests are merged")
+	flag.Var(&teams, "team", "Grant access to the repository to organisation team by its slug. You can grant permissions using following format: <team>:<permission>. Permission can be: pull, triage, push, maintain or admin, default is push. Can be specified multiple times. Example: --team developers:push")
+	flag.Var(&literalSecrets, "secret-literal", "Create GitHub Actions secret from literal (eq. --secret-literal=secret_name=value)")
+	flag.Var(&fileSecrets, "secret-file", "Create GitHub Actions secret from file (eq. --secret-file=secret_name=./path/to/file)")
+	flag.Var(&vaultSecrets, "secret-vault", "Create GitHub Actions secret from HashiCorp Vault KV secret (eq. --secret-vault=secret_name=secret/path#field)")
+	flag.Var(&variables, "variable", "Create GitHub Actions variable (eq. --variable=NAME=value). Can be specified multiple times")
+	flag.Var(&environments, "environment", "Create deployment environment (eq. --environment=production). Can be specified multiple times")
+	flag.Var(&webhooks, "webhook", "Add webhook sending push events as JSON to a given URL, use config file to set other events and secret. Can be specified multiple times")
+	flag.StringVar(&template, "template", "", "Create repository from GitHub template repository (eq. --template=adobe/go-template), local files are committed on top of it")
+	flag.StringVar(&fork, "fork", "", "Create repository as a fork of upstream repository (eq. --fork=adobe/go-starter), local files are committed on top of it")
+	flag.BoolVar(&reconcile, "reconcile", false, "Configure existing repository: compare it with desired state and apply only missing changes")
+	flag.BoolVar(&dryRun, "dry-run", false, "Print plan of changes without applying it")
+	flag.StringVar(&config, "config", "", "Path to YAML file with repository configuration, values passed with flags take precedence")
+	flag.IntVar(&protection.RequiredReviews, "required-reviews", 0, "Protect branch by requiring a given number of approving reviews of pull requests")
+	flag.BoolVar(&protection.DismissStaleReviews, "dismiss-stale-reviews", false, "Protect branch by dismissing approving reviews when new commits are pushed")
+	flag.BoolVar(&protection.RequireCodeOwnerReviews, "require-code-owner-reviews", false, "Protect branch by requiring approving review of code owners")
+	flag.Var((*SliceFlag)(&protection.RequiredStatusChecks), "required-status-check", "Protect branch by requiring status check to pass before merging. Can be specified multiple times. Example: --required-status-check continuous-integration/drone/pr")
+	flag.BoolVar(&protection.StrictStatusChecks, "strict-status-checks", false, "Protect branch by requiring branches to be up to date before merging")
+	flag.BoolVar(&protection.EnforceAdmins, "enforce-admins", false, "Enforce branch protection rules for administrators")
+	flag.Var((*SliceFlag)(&protection.RestrictPushUsers), "restrict-push-user", "Protect branch by allowing only given users to push to it (organisation repositories only). Can be specified multiple times")
+	flag.Var((*SliceFlag)(&protection.RestrictPushTeams), "restrict-push-team", "Protect branch by allowing only given teams to push to it (organisation repositories only). Can be specified multiple times")
 	flag.Var(&collaborators, "collaborator", "Add collaborators to the repository by GitHub username. You can grant permissions using following format: <username>:<permission>. Permission can be: pull (read only), push (read and write) or admin (everything), default is push. Can be specified multiple times. Example: --collaborator octocat:pull")
 	flag.Parse()
 
@@ -69,164 +107,392 @@ func main() {
 		ui.Fatalf("GitHub repository name is empty\n")
 	}
 
-	// get credentials from OSX keychain
-	user, pass, err := keychainx.Load("github.com")
-	if err != nil && err != keychainx.ErrNotFound {
-		ui.Fatalf("An error occurred while loading keychain: %v\n", err)
+	var cfg Config
+	if config != "" {
+		var err error
+		if cfg, err = LoadConfig(config); err != nil {
+			ui.Fatalf("An error occurred while loading config: %v\n", err)
+		}
+	}
+
+	if description != "" {
+		cfg.Description = description
+	}
+
+	if homepage != "" {
+		cfg.Homepage = homepage
+	}
+
+	if len(topics) > 0 {
+		cfg.Topics = topics
+	}
+
+	if squashOnly {
+		cfg.Merge.AllowMergeCommit = BoolPtr(false)
+		cfg.Merge.AllowSquashMerge = BoolPtr(true)
+		cfg.Merge.AllowRebaseMerge = BoolPtr(false)
+	}
+
+	if deleteBranch {
+		cfg.Merge.DeleteBranchOnMerge = BoolPtr(true)
+	}
+
+	for _, t := range teams {
+		slug, perm := SplitPermissions(t, "push")
+		cfg.Teams = append(cfg.Teams, Team{Name: slug, Permission: perm})
 	}
 
-	if err == keychainx.ErrNotFound {
-		user, pass = AskCredentials(ui)
-		if err := keychainx.Save("github.com", user, pass); err != nil {
-			ui.Errorf("An error occurred while saving keychain: %v\n", err)
+	for _, v := range variables {
+		name, value := SplitKeyValue(v)
+
+		if cfg.Actions.Variables == nil {
+			cfg.Actions.Variables = map[string]string{}
 		}
+
+		cfg.Actions.Variables[name] = value
 	}
 
+	cfg.Actions.Environments = append(cfg.Actions.Environments, environments...)
+
+	for _, w := range webhooks {
+		cfg.Webhooks = append(cfg.Webhooks, Webhook{URL: w})
+	}
+
+	cfg.Protection.Merge(protection)
+
+	if template != "" && fork != "" {
+		ui.Fatalf("Flags -template and -fork can't be used together\n")
+	}
+
+	// secrets are read before repository is changed
+	secrets, err := keychainx.ReadSecrets(literalSecrets, fileSecrets, vaultSecrets)
+	if err != nil {
+		ui.Fatalf("An error occurred while reading secrets: %v\n", err)
+	}
+
+	u, err := auth.GitHubURL(host)
+	if err != nil {
+		ui.Fatalf("%v\n", err)
+	}
+
+	// public repositories can be created with public_repo scope
+	scopes := []string{"repo"}
+	if public {
+		scopes = []string{"public_repo"}
+	}
+
+	token, login := Authenticate(ui, host, org, creds, scopes)
+
 	// build github client
-	cli := github.NewClient(&http.Client{
-		Timeout: 30 * time.Second,
-		Transport: &github.BasicAuthTransport{
-			Username: user,
-			Password: pass,
-		},
-	})
-
-	// get authenticated user so we can check if org value is username
-	self, _, err := cli.Users.Get(context.Background(), "")
+	cli, err := auth.NewGitHubClient(host, token)
 	if err != nil {
-		ui.Fatalf("An error occurred while fetching username from GitHub: %v\n", err)
+		ui.Fatalf("An error occurred while creating GitHub client: %v\n", err)
 	}
 
-	// GitHub API requires org to be empty when creating repository under "current" account
-	createOrg := org
-	if self.Login != nil && createOrg == *self.Login {
-		createOrg = ""
+	// repository, collaborators, deployment key, secrets and branch protection are provisioned the same way as by
+	// go-starter-scm, GitHub specific settings use the client directly
+	provider := scm.NewGitHub(cli)
+
+	// GitHub API requires org to be empty when forking repository into "current" account
+	forkOrg := org
+	if forkOrg == login {
+		forkOrg = ""
 	}
 
-	// create repository
-	repo, _, err := cli.Repositories.Create(context.Background(), createOrg, &github.Repository{
-		Name:         github.String(name),
-		Private:      github.Bool(!public),
-		HasIssues:    github.Bool(issues),
-		HasProjects:  github.Bool(projects),
-		HasWiki:      github.Bool(wiki),
-		MasterBranch: github.String(branch),
-	})
+	ctx := context.Background()
+
+	repo, _, err := cli.Repositories.Get(ctx, org, name)
+	if e, ok := err.(*github.ErrorResponse); ok && e.Response != nil && e.Response.StatusCode == http.StatusNotFound {
+		repo, err = nil, nil
+	}
 
 	if err != nil {
-		if !strings.Contains(err.Error(), "name already exists on this account") {
-			ui.Fatalf("An error occurred while creating GitHub repository: %v\n", err)
-		}
+		ui.Fatalf("An error occurred while reading GitHub repository: %v\n", err)
+	}
 
-		ui.Printf("Repository %v/%v already exists, skipping repository configuration...\n", org, name)
+	if repo != nil && !reconcile {
+		ui.Printf("Repository %v/%v already exists, skipping repository configuration. Use -reconcile flag to configure it...\n", org, name)
 		return
 	}
 
-	ui.Successf("New repository created at %v\n", *repo.HTMLURL)
+	desired := &github.Repository{
+		Private:     github.Bool(!public),
+		HasIssues:   github.Bool(issues),
+		HasProjects: github.Bool(projects),
+		HasWiki:     github.Bool(wiki),
+	}
 
-	// init repository
-	if err := run("git", "init"); err != nil {
-		ui.Fatalf("An error occurred while running git init: %v\n", err)
+	// GitHub App has no git credentials so its token is passed to git
+	var env []string
+	if creds.AppID != 0 {
+		env = append(env, "GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=http.extraheader", "GIT_CONFIG_VALUE_0=AUTHORIZATION: basic "+base64.StdEncoding.EncodeToString([]byte("x-access-token:"+token)))
 	}
 
-	// add all files
-	if err := run("git", "add", "-A"); err != nil {
-		ui.Fatalf("An error occurred while running git add: %v\n", err)
+	var plan Plan
+
+	// create repository or update visibility and features of existing one, repository created from template or as
+	// a fork gets features of the original
+	switch {
+	case repo == nil && template != "":
+		plan.Add(true, fmt.Sprintf("create repository from template %v", template), func() error {
+			created, err := GenerateFromTemplate(cli, template, org, name, !public)
+			if err != nil {
+				return err
+			}
+
+			repo = created
+			ui.Successf("New repository created at %v\n", repo.GetHTMLURL())
+			return nil
+		})
+	case repo == nil && fork != "":
+		plan.Add(true, fmt.Sprintf("fork repository %v", fork), func() error {
+			created, err := Fork(cli, fork, forkOrg, name)
+			if err != nil {
+				return err
+			}
+
+			repo = created
+			ui.Successf("New repository forked at %v\n", repo.GetHTMLURL())
+			return nil
+		})
+	case repo == nil:
+		plan.Add(true, "create repository", func() error {
+			created, err := provider.CreateRepository(org, name, !public)
+			if err != nil {
+				return err
+			}
+
+			ui.Successf("New repository created at %v\n", created.WebURL)
+
+			repo, _, err = cli.Repositories.Get(ctx, org, name)
+			return err
+		})
+	default:
+		if edit := EditFeatures(repo, desired); edit != nil {
+			plan.Add(false, DescribeFeatures(edit), func() error {
+				_, _, err := cli.Repositories.Edit(ctx, org, name, edit)
+				return err
+			})
+		}
 	}
 
-	// remove starter files
-	if err := run("git", "rm", "--cached", "--ignore-unmatch", ".starter*"); err != nil {
-		ui.Fatalf("An error occurred while running git rm: %v\n", err)
+	// features are updated once repository is created, visibility of forks can't be changed
+	based := template != "" || fork != ""
+	if repo == nil {
+		features := *desired
+		if fork != "" {
+			features.Private = nil
+		}
+
+		plan.Add(false, "update features of the repository", func() error {
+			edit := EditFeatures(repo, &features)
+			if edit == nil {
+				return nil
+			}
+
+			_, _, err := cli.Repositories.Edit(ctx, org, name, edit)
+			return err
+		})
 	}
 
-	// commit
-	if err := run("git", "commit", "-m", "Initial commit"); err != nil {
-		ui.Fatalf("An error occurred while running git commit: %v\n", err)
+	// add remote, GitHub Enterprise could omit clone URL in older versions
+	cloneURL := repo.GetCloneURL()
+	if cloneURL == "" {
+		cloneURL = fmt.Sprintf("%v/%v/%v.git", u, org, name)
 	}
 
-	// add remote
-	if err := run("git", "remote", "add", remote, *repo.CloneURL); err != nil {
-		ui.Fatalf("An error occurred while running git remote add: %v\n", err)
+	pushed := false
+	if repo != nil {
+		if pushed, err = HasBranch(cli, org, name, branch); err != nil {
+			ui.Fatalf("An error occurred while reading branch %#v: %v\n", branch, err)
+		}
 	}
 
-	// push to remote
-	if err := run("git", "push", "--set-upstream", remote, branch); err != nil {
-		ui.Fatalf("An error occurred while running git push: %v\n", err)
+	push := Push{
+		Env:      env,
+		Remote:   remote,
+		CloneURL: cloneURL,
+		SSHURL:   repo.GetSSHURL(),
+		Branch:   branch,
+		Based:    based,
+		Base:     func() string { return repo.GetDefaultBranch() },
+		Pushed:   pushed,
 	}
 
-	for _, c := range collaborators {
-		user, perm := SplitPermissions(c, "push")
+	push.Plan(&plan)
+
+	if settings := RepositorySettings(cfg); len(settings) > 0 {
+		plan.Add(false, "update repository settings", func() error {
+			return EditRepository(cli, org, name, settings)
+		})
+	}
+
+	if len(cfg.Topics) > 0 {
+		plan.Add(false, fmt.Sprintf("set topics %v", strings.Join(cfg.Topics, ", ")), func() error {
+			return SetTopics(cli, org, name, cfg.Topics)
+		})
+	}
 
-		_, err := cli.Repositories.AddCollaborator(context.Background(), org, name, user, &github.RepositoryAddCollaboratorOptions{
-			Permission: perm,
+	for _, l := range cfg.Labels {
+		l := l
+		plan.Add(false, fmt.Sprintf("create or update label %#v", l.Name), func() error {
+			return CreateOrUpdateLabel(cli, org, name, l)
 		})
+	}
+
+	for _, e := range cfg.Actions.Environments {
+		e := e
+		plan.Add(false, fmt.Sprintf("create environment %#v", e), func() error {
+			return CreateEnvironment(cli, org, name, e)
+		})
+	}
+
+	// variables are sorted to keep the plan stable
+	var names []string
+	for n := range cfg.Actions.Variables {
+		names = append(names, n)
+	}
+
+	sort.Strings(names)
 
+	for _, n := range names {
+		n, v := n, cfg.Actions.Variables[n]
+		plan.Add(false, fmt.Sprintf("set Actions variable %#v", n), func() error {
+			return SetVariable(cli, org, name, n, v)
+		})
+	}
+
+	for _, s := range secrets {
+		s := s
+		plan.Add(false, fmt.Sprintf("set Actions secret %#v", s.Name), func() error {
+			return provider.SetSecret(org, name, s.Name, s.Value)
+		})
+	}
+
+	// existing webhooks are matched by URL
+	hooks := map[string]*github.Hook{}
+	if repo != nil && len(cfg.Webhooks) > 0 {
+		if hooks, err = Webhooks(cli, org, name); err != nil {
+			ui.Fatalf("An error occurred while reading webhooks: %v\n", err)
+		}
+	}
+
+	for _, w := range cfg.Webhooks {
+		secret, err := w.Secret()
 		if err != nil {
-			ui.Errorf("An error occurred while adding %#v collaborator: %v\n", c, err)
+			ui.Fatalf("An error occurred while reading secret of webhook %v: %v\n", w.URL, err)
+		}
+
+		hook, existing := w.Hook(secret), hooks[w.URL]
+
+		switch {
+		case existing == nil:
+			plan.Add(false, fmt.Sprintf("create webhook %v", w.URL), func() error {
+				return CreateOrUpdateWebhook(cli, org, name, nil, hook)
+			})
+		case !WebhookUpToDate(existing, hook):
+			plan.Add(false, fmt.Sprintf("update webhook %v", w.URL), func() error {
+				return CreateOrUpdateWebhook(cli, org, name, existing, hook)
+			})
 		}
 	}
 
+	if cfg.Protection.Enabled() {
+		plan.Add(false, fmt.Sprintf("protect branch %#v", branch), func() error {
+			return provider.ProtectBranch(org, name, branch, cfg.Protection)
+		})
+	}
+
+	// existing collaborators are added again only when their permissions differ
+	actual := map[string]string{}
+	if repo != nil {
+		if actual, err = Collaborators(cli, org, name); err != nil {
+			ui.Fatalf("An error occurred while reading collaborators: %v\n", err)
+		}
+	}
+
+	for _, c := range collaborators {
+		user, perm := SplitPermissions(c, "push")
+		if actual[strings.ToLower(user)] == perm {
+			continue
+		}
+
+		plan.Add(false, fmt.Sprintf("add collaborator %#v with %#v permissions", user, perm), func() error {
+			return provider.AddCollaborator(org, name, user, scm.Permission(perm))
+		})
+	}
+
+	for _, t := range cfg.Teams {
+		t := t
+		plan.Add(false, fmt.Sprintf("grant team %#v access to the repository", t.Name), func() error {
+			return AddTeam(cli, org, org, name, t)
+		})
+	}
+
 	if deployKey != "" {
 		key, perm := SplitPermissions(deployKey, "ro")
 
-		ui.Printf("Adding deployment key %#v with %#v permissions\n", key, perm)
-
 		data, err := ioutil.ReadFile(key)
 		if err != nil {
 			ui.Fatalf("Unable to read deployment key: %v\n", err)
 		}
 
-		_, _, err = cli.Repositories.CreateKey(context.Background(), org, name, &github.Key{
-			Title:    StringPtr("Deploy Key"),
-			Key:      StringPtr(string(data)),
-			ReadOnly: BoolPtr(perm != "rw"),
-		})
+		exists := false
+		if repo != nil {
+			if exists, err = HasDeployKey(cli, org, name, string(data), perm != "rw"); err != nil {
+				ui.Fatalf("An error occurred while reading deployment keys: %v\n", err)
+			}
+		}
 
-		if err != nil {
-			ui.Errorf("An error occurred while adding %#v deployment key: %v\n", key, err)
+		if !exists {
+			plan.Add(false, fmt.Sprintf("add deployment key %#v with %#v permissions", key, perm), func() error {
+				return provider.AddDeployKey(org, name, "Deploy Key", string(data), perm != "rw")
+			})
 		}
 	}
+
+	if len(plan) == 0 {
+		ui.Successf("Repository %v/%v is up to date\n", org, name)
+		return
+	}
+
+	ui.Printf("Plan for %v/%v:\n", org, name)
+	plan.Print(ui)
+
+	if dryRun {
+		return
+	}
+
+	if plan.Apply(ui) {
+		ui.Successf("Repository %v/%v is configured\n", org, name)
+	}
+}
+
+// hostDefault returns GitHub host from GITHUB_HOST environment variable or github.com
+func hostDefault() string {
+	if host := os.Getenv("GITHUB_HOST"); host != "" {
+		return host
+	}
+
+	return auth.GitHubHost
 }
 
 // Run a cli command
 func run(name string, args ...string) error {
+	return runWithEnv(nil, name, args...)
+}
+
+// Run a cli command with additional environment variables
+func runWithEnv(env []string, name string, args ...string) error {
 	cmd := exec.Command(name, args...)
 	cmd.Stderr = os.Stderr
 	cmd.Stdout = os.Stdout
 	cmd.Stdin = os.Stdin
-	cmd.Env = os.Environ()
+	cmd.Env = append(os.Environ(), env...)
 
 	return cmd.Run()
 }
 
-func AskCredentials(ui *console.Console) (user string, pass string) {
-	for {
-		user = ui.ReadString("Enter your GitHub username: ")
-
-		ui.Printf("\n")
-		ui.Printf("Follow this link and generate personal token: https://git.io/fjisU. Scroll to the bottom of the page and click \"Generate token\".\n")
-
-		pass = ui.ReadString("Enter your personal token: ")
-
-		// build github client
-		cli := github.NewClient(&http.Client{
-			Timeout: 30 * time.Second,
-			Transport: &github.BasicAuthTransport{
-				Username: user,
-				Password: pass,
-			},
-		})
-
-		_, _, err := cli.Zen(context.Background())
-		if err == nil {
-			return
-		}
-
-		ui.Errorf("An error occurred while validating credentials: %v\n", err)
-		ui.Errorf("Credentials do not appear to be valid, try again...\n")
-	}
-}
-
 func SplitPermissions(c, d string) (string, string) {
 	if parts := strings.SplitN(c, ":", 2); len(parts) == 2 {
 		return parts[0], parts[1]
@@ -235,6 +501,14 @@ func SplitPermissions(c, d string) (string, string) {
 	return c, d
 }
 
+func SplitKeyValue(c string) (string, string) {
+	if parts := strings.SplitN(c, "=", 2); len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+
+	return c, ""
+}
+
 type SliceFlag []string
 
 func (s *SliceFlag) Set(v string) error {
@@ -0,0 +1,92 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"github.com/adobe/go-starter/pkg/scm"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	file, err := ioutil.TempFile("", "go-starter-github")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(file.Name())
+
+	_, _ = file.WriteString(`
+protection:
+  required_reviews: 2
+  required_status_checks: [ "ci/drone" ]
+  strict_status_checks: true
+  restrict_push_teams: [ "admins" ]
+`)
+	_ = file.Close()
+
+	cfg, err := LoadConfig(file.Name())
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+
+	cfg.Protection.Merge(Protection{RequiredReviews: 1, EnforceAdmins: true})
+
+	want := Protection{
+		RequiredReviews:      1,
+		RequiredStatusChecks: []string{"ci/drone"},
+		StrictStatusChecks:   true,
+		EnforceAdmins:        true,
+		RestrictPushTeams:    []string{"admins"},
+	}
+
+	if !reflect.DeepEqual(cfg.Protection, want) {
+		t.Errorf("Protection does not match: got %#v, want %#v", cfg.Protection, want)
+	}
+}
+
+func TestProtect(t *testing.T) {
+	server, cli := newFakeGitHub(t, map[string]response{
+		"PUT /api/v3/repos/adobe/awesome/branches/master/protection": {},
+	})
+
+	defer server.Close()
+
+	if (Protection{}).Enabled() {
+		t.Errorf("Empty protection must be disabled")
+	}
+
+	p := Protection{DismissStaleReviews: true, RestrictPushUsers: []string{"octocat"}}
+	if err := scm.NewGitHub(cli).ProtectBranch("adobe", "awesome", "master", p); err != nil {
+		t.Fatalf("ProtectBranch() failed: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"required_status_checks": nil,
+		"required_pull_request_reviews": map[string]interface{}{
+			"dismiss_stale_reviews":           true,
+			"require_code_owner_reviews":      false,
+			"required_approving_review_count": float64(1),
+		},
+		"enforce_admins": false,
+		"restrictions": map[string]interface{}{
+			"users": []interface{}{"octocat"},
+			"teams": []interface{}{},
+		},
+	}
+
+	if got := server.requests["PUT /api/v3/repos/adobe/awesome/branches/master/protection"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Request does not match: got %#v, want %#v", got, want)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"fmt"
+)
+
+// Push of local project into GitHub repository
+type Push struct {
+	// Env is passed to git commands which talk to the remote
+	Env      []string
+	Remote   string
+	CloneURL string
+	SSHURL   string
+	Branch   string
+	// Based is set for repository created from template or as a fork, files are committed on top of its history
+	Based bool
+	// Base returns default branch of the repository, it's known only once repository is created
+	Base func() string
+	// Pushed is set when branch exists in the repository already
+	Pushed bool
+}
+
+// Plan steps which commit files unless local repository has commits already, add or update the remote and push the
+// branch. Starter files are removed but recorded answers are kept so project can be upgraded later.
+func (p Push) Plan(plan *Plan) {
+	_, err := gitOutput("rev-parse", "--verify", "--quiet", "HEAD")
+	committed := err == nil
+
+	if !committed && !p.Based {
+		plan.Add(true, "commit files to local repository", func() error {
+			commands := [][]string{
+				{"init"},
+				{"add", "-A"},
+				{"rm", "-r", "--cached", "--ignore-unmatch", ".starter", ".starter.yml"},
+				{"commit", "-m", "Initial commit"},
+			}
+
+			for _, args := range commands {
+				if err := run("git", args...); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+
+	// remote can't be added until local repository exists
+	if !committed && p.Based {
+		plan.Add(true, "initialize local repository", func() error {
+			return run("git", "init")
+		})
+	}
+
+	if current, err := gitOutput("remote", "get-url", p.Remote); err != nil {
+		plan.Add(true, fmt.Sprintf("add remote %#v with URL %v", p.Remote, p.CloneURL), func() error {
+			return run("git", "remote", "add", p.Remote, p.CloneURL)
+		})
+	} else if current != p.CloneURL && current != p.SSHURL {
+		plan.Add(true, fmt.Sprintf("change URL of remote %#v from %v to %v", p.Remote, current, p.CloneURL), func() error {
+			return run("git", "remote", "set-url", p.Remote, p.CloneURL)
+		})
+	}
+
+	// files are committed on top of template or upstream once remote is added
+	if !committed && p.Based {
+		plan.Add(true, "commit files on top of the repository", func() error {
+			base := p.Base()
+			if base == "" {
+				base = p.Branch
+			}
+
+			return CommitOnTop(p.Env, p.Remote, base, p.Branch)
+		})
+	}
+
+	if !p.Pushed {
+		plan.Add(true, fmt.Sprintf("push branch %#v to %#v remote", p.Branch, p.Remote), func() error {
+			return runWithEnv(p.Env, "git", "push", "--set-upstream", p.Remote, p.Branch)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"bytes"
+	"github.com/adobe/go-starter/pkg/console"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPushPlan(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-starter-github")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"GIT_AUTHOR_NAME", "GIT_AUTHOR_EMAIL", "GIT_COMMITTER_NAME", "GIT_COMMITTER_EMAIL"} {
+		defer os.Setenv(name, os.Getenv(name))
+		os.Setenv(name, "go-starter")
+	}
+
+	// new repositories start on master branch regardless of git configuration
+	env := map[string]string{"GIT_CONFIG_COUNT": "1", "GIT_CONFIG_KEY_0": "init.defaultBranch", "GIT_CONFIG_VALUE_0": "master"}
+	for name, value := range env {
+		if current, ok := os.LookupEnv(name); ok {
+			defer os.Setenv(name, current)
+		} else {
+			defer os.Unsetenv(name)
+		}
+
+		os.Setenv(name, value)
+	}
+
+	defer func(n int) { fetchAttempts = n }(fetchAttempts)
+
+	fetchAttempts = 1
+
+	git := func(dir string, args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+
+		return string(out)
+	}
+
+	// remote repository created from template has template history
+	template, remote := filepath.Join(dir, "template"), filepath.Join(dir, "remote.git")
+
+	git(dir, "init", "--bare", remote)
+	git(dir, "init", template)
+	_ = ioutil.WriteFile(filepath.Join(template, "README.md"), []byte("template"), 0644)
+	git(template, "add", "-A")
+	git(template, "commit", "-m", "Template")
+	git(template, "push", remote, "HEAD:refs/heads/main")
+
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+
+	tests := []struct {
+		name    string
+		branch  string
+		based   bool
+		steps   []string
+		history string
+	}{
+		{
+			name:    "from-template",
+			branch:  "develop",
+			based:   true,
+			steps:   []string{"initialize local repository", "add remote", "commit files on top of the repository", "push branch"},
+			history: "Apply go-starter answers\nTemplate\n",
+		},
+		{
+			name:    "from-scratch",
+			branch:  "master",
+			based:   false,
+			steps:   []string{"commit files to local repository", "add remote", "push branch"},
+			history: "Initial commit\n",
+		},
+	}
+
+	for _, test := range tests {
+		// freshly generated project has no local repository
+		project, branch := filepath.Join(dir, test.name), test.branch
+		_ = os.Mkdir(project, 0755)
+		_ = ioutil.WriteFile(filepath.Join(project, "README.md"), []byte("awesome"), 0644)
+		_ = ioutil.WriteFile(filepath.Join(project, ".starter.yml"), []byte("questions: []"), 0644)
+
+		if err := os.Chdir(project); err != nil {
+			t.Fatal(err)
+		}
+
+		push := Push{Remote: "upstream", CloneURL: remote, Branch: branch, Based: test.based, Base: func() string { return "main" }}
+
+		var plan Plan
+		push.Plan(&plan)
+
+		if len(plan) != len(test.steps) {
+			t.Fatalf("Plan does not match: got %#v, want %#v", plan, test.steps)
+		}
+
+		for j, s := range plan {
+			if !strings.HasPrefix(s.Description, test.steps[j]) {
+				t.Errorf("Step %v does not match: got %#v, want %#v", j, s.Description, test.steps[j])
+			}
+		}
+
+		out := &bytes.Buffer{}
+		if !plan.Apply(console.New(strings.NewReader(""), out)) {
+			t.Fatalf("Apply() failed: %s", out)
+		}
+
+		if log := git(remote, "log", "--format=%s", branch); log != test.history {
+			t.Errorf("History of pushed branch does not match: got %#v, want %#v", log, test.history)
+		}
+	}
+}
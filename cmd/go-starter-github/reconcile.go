@@ -0,0 +1,244 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"context"
+	"github.com/adobe/go-starter/pkg/console"
+	"github.com/google/go-github/github"
+	"net/http"
+	"os/exec"
+	"strings"
+)
+
+// Step of a plan which brings repository to desired state
+type Step struct {
+	Description string
+	// Fatal step stops the plan when it fails, following steps depend on it
+	Fatal bool
+	Apply func() error
+}
+
+// Plan is a list of steps applied in order
+type Plan []Step
+
+// Add step to the plan
+func (p *Plan) Add(fatal bool, description string, apply func() error) {
+	*p = append(*p, Step{Description: description, Fatal: fatal, Apply: apply})
+}
+
+// Print steps of the plan
+func (p Plan) Print(ui *console.Console) {
+	for _, s := range p {
+		ui.Printf("  - %v\n", s.Description)
+	}
+}
+
+// Apply steps of the plan, errors of non-fatal steps are reported and following steps are applied anyway. Returns
+// false if any step failed.
+func (p Plan) Apply(ui *console.Console) bool {
+	ok := true
+
+	for _, s := range p {
+		err := s.Apply()
+		if err == nil {
+			continue
+		}
+
+		if s.Fatal {
+			ui.Fatalf("An error occurred while trying to %v: %v\n", s.Description, err)
+		}
+
+		ui.Errorf("An error occurred while trying to %v: %v\n", s.Description, err)
+		ok = false
+	}
+
+	return ok
+}
+
+// EditFeatures returns changes of visibility and features of existing repository, nil is returned when there is
+// nothing to change. Fields which are not set in desired repository are not changed.
+func EditFeatures(actual, desired *github.Repository) *github.Repository {
+	edit := &github.Repository{}
+	changed := false
+
+	if desired.Private != nil && actual.GetPrivate() != desired.GetPrivate() {
+		edit.Private, changed = desired.Private, true
+	}
+
+	if desired.HasIssues != nil && actual.GetHasIssues() != desired.GetHasIssues() {
+		edit.HasIssues, changed = desired.HasIssues, true
+	}
+
+	if desired.HasProjects != nil && actual.GetHasProjects() != desired.GetHasProjects() {
+		edit.HasProjects, changed = desired.HasProjects, true
+	}
+
+	if desired.HasWiki != nil && actual.GetHasWiki() != desired.GetHasWiki() {
+		edit.HasWiki, changed = desired.HasWiki, true
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return edit
+}
+
+// DescribeFeatures returns human readable description of changes returned by EditFeatures
+func DescribeFeatures(edit *github.Repository) string {
+	var changes []string
+
+	if edit.Private != nil {
+		if edit.GetPrivate() {
+			changes = append(changes, "make repository private")
+		} else {
+			changes = append(changes, "make repository public")
+		}
+	}
+
+	features := []struct {
+		name    string
+		enabled *bool
+	}{
+		{"issues", edit.HasIssues},
+		{"projects", edit.HasProjects},
+		{"wiki", edit.HasWiki},
+	}
+
+	for _, f := range features {
+		switch {
+		case f.enabled == nil:
+		case *f.enabled:
+			changes = append(changes, "enable "+f.name)
+		default:
+			changes = append(changes, "disable "+f.name)
+		}
+	}
+
+	return strings.Join(changes, ", ")
+}
+
+// Collaborators returns permissions of repository collaborators including pending invitations, permission is one of
+// pull, push or admin
+func Collaborators(cli *github.Client, owner, repo string) (map[string]string, error) {
+	ctx := context.Background()
+	perms := map[string]string{}
+
+	opt := &github.ListCollaboratorsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		users, resp, err := cli.Repositories.ListCollaborators(ctx, owner, repo, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, u := range users {
+			var p map[string]bool
+			if u.Permissions != nil {
+				p = *u.Permissions
+			}
+
+			switch {
+			case p["admin"]:
+				perms[strings.ToLower(u.GetLogin())] = "admin"
+			case p["push"]:
+				perms[strings.ToLower(u.GetLogin())] = "push"
+			default:
+				perms[strings.ToLower(u.GetLogin())] = "pull"
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opt.Page = resp.NextPage
+	}
+
+	// invitations use read and write instead of pull and push
+	invitePerms := map[string]string{"read": "pull", "write": "push", "admin": "admin"}
+
+	lopt := &github.ListOptions{PerPage: 100}
+	for {
+		invitations, resp, err := cli.Repositories.ListInvitations(ctx, owner, repo, lopt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, i := range invitations {
+			perm, ok := invitePerms[i.GetPermissions()]
+			if !ok {
+				perm = i.GetPermissions()
+			}
+
+			perms[strings.ToLower(i.GetInvitee().GetLogin())] = perm
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		lopt.Page = resp.NextPage
+	}
+
+	return perms, nil
+}
+
+// HasDeployKey checks if repository has deployment key with the same key material and permissions, key comment is
+// ignored
+func HasDeployKey(cli *github.Client, owner, repo, key string, readOnly bool) (bool, error) {
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		keys, resp, err := cli.Repositories.ListKeys(context.Background(), owner, repo, opt)
+		if err != nil {
+			return false, err
+		}
+
+		for _, k := range keys {
+			if keyMaterial(k.GetKey()) == keyMaterial(key) && k.GetReadOnly() == readOnly {
+				return true, nil
+			}
+		}
+
+		if resp.NextPage == 0 {
+			return false, nil
+		}
+
+		opt.Page = resp.NextPage
+	}
+}
+
+// keyMaterial returns type and data of SSH public key without comment
+func keyMaterial(key string) string {
+	fields := strings.Fields(key)
+	if len(fields) > 2 {
+		fields = fields[:2]
+	}
+
+	return strings.Join(fields, " ")
+}
+
+// HasBranch checks if branch exists in the repository
+func HasBranch(cli *github.Client, owner, repo, branch string) (bool, error) {
+	_, _, err := cli.Repositories.GetBranch(context.Background(), owner, repo, branch)
+	if e, ok := err.(*github.ErrorResponse); ok && e.Response != nil && e.Response.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// gitOutput runs git command and returns its trimmed output
+func gitOutput(args ...string) (string, error) {
+	out, err := exec.Command("git", args...).Output()
+	return strings.TrimSpace(string(out)), err
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"github.com/adobe/go-starter/pkg/console"
+	"github.com/google/go-github/github"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEditFeatures(t *testing.T) {
+	tests := []struct {
+		actual      *github.Repository
+		desired     *github.Repository
+		description string
+	}{
+		{
+			actual:      &github.Repository{Private: BoolPtr(true), HasIssues: BoolPtr(false)},
+			desired:     &github.Repository{Private: BoolPtr(true), HasIssues: BoolPtr(false)},
+			description: "",
+		},
+		{
+			actual:      &github.Repository{Private: BoolPtr(true), HasWiki: BoolPtr(true)},
+			desired:     &github.Repository{Private: BoolPtr(false), HasIssues: BoolPtr(true), HasWiki: BoolPtr(false)},
+			description: "make repository public, enable issues, disable wiki",
+		},
+	}
+
+	for _, test := range tests {
+		edit := EditFeatures(test.actual, test.desired)
+
+		if test.description == "" {
+			if edit != nil {
+				t.Errorf("Changes must be empty: got %#v", edit)
+			}
+
+			continue
+		}
+
+		if edit == nil {
+			t.Errorf("Changes must not be empty for %#v", test.description)
+			continue
+		}
+
+		if got := DescribeFeatures(edit); got != test.description {
+			t.Errorf("Description does not match: got %#v, want %#v", got, test.description)
+		}
+	}
+}
+
+func TestActualState(t *testing.T) {
+	server, cli := newFakeGitHub(t, map[string]response{
+		"GET /api/v3/repos/adobe/awesome/collaborators": {
+			body:   `[{"login":"Octocat","permissions":{"admin":true,"push":true,"pull":true}}]`,
+			header: map[string]string{"Link": `</api/v3/repos/adobe/awesome/collaborators?page=2&per_page=100>; rel="next"`},
+		},
+		"GET /api/v3/repos/adobe/awesome/collaborators?page=2&per_page=100": {body: `[{"login":"hubot","permissions":{"pull":true}}]`},
+		"GET /api/v3/repos/adobe/awesome/invitations":                       {body: `[{"invitee":{"login":"monalisa"},"permissions":"write"}]`},
+		"GET /api/v3/repos/adobe/awesome/keys":                              {body: `[{"key":"ssh-rsa AAAA","read_only":true}]`},
+		"GET /api/v3/repos/adobe/awesome/branches/master":                   {body: `{"name":"master"}`},
+	})
+
+	defer server.Close()
+
+	perms, err := Collaborators(cli, "adobe", "awesome")
+	if err != nil {
+		t.Fatalf("Collaborators() failed: %v", err)
+	}
+
+	want := map[string]string{"octocat": "admin", "hubot": "pull", "monalisa": "push"}
+	if !reflect.DeepEqual(perms, want) {
+		t.Errorf("Collaborators do not match: got %#v, want %#v", perms, want)
+	}
+
+	keys := []struct {
+		key      string
+		readOnly bool
+		want     bool
+	}{
+		{"ssh-rsa AAAA user@example.com\n", true, true},
+		{"ssh-rsa AAAA", false, false},
+		{"ssh-rsa BBBB", true, false},
+	}
+
+	for _, k := range keys {
+		got, err := HasDeployKey(cli, "adobe", "awesome", k.key, k.readOnly)
+		if err != nil {
+			t.Fatalf("HasDeployKey() failed: %v", err)
+		}
+
+		if got != k.want {
+			t.Errorf("HasDeployKey(%#v, %v) does not match: got %v, want %v", k.key, k.readOnly, got, k.want)
+		}
+	}
+
+	for branch, want := range map[string]bool{"master": true, "develop": false} {
+		got, err := HasBranch(cli, "adobe", "awesome", branch)
+		if err != nil {
+			t.Fatalf("HasBranch() failed: %v", err)
+		}
+
+		if got != want {
+			t.Errorf("HasBranch(%#v) does not match: got %v, want %v", branch, got, want)
+		}
+	}
+}
+
+func TestPlan(t *testing.T) {
+	var applied []string
+	var plan Plan
+
+	plan.Add(true, "first", func() error {
+		applied = append(applied, "first")
+		return nil
+	})
+
+	plan.Add(false, "second", func() error {
+		applied = append(applied, "second")
+		return errors.New("failed")
+	})
+
+	plan.Add(false, "third", func() error {
+		applied = append(applied, "third")
+		return nil
+	})
+
+	out := &bytes.Buffer{}
+	ui := console.New(strings.NewReader(""), out)
+
+	plan.Print(ui)
+
+	if ok := plan.Apply(ui); ok {
+		t.Errorf("Apply() must report failed step")
+	}
+
+	if want := []string{"first", "second", "third"}; !reflect.DeepEqual(applied, want) {
+		t.Errorf("Applied steps do not match: got %#v, want %#v", applied, want)
+	}
+
+	if !strings.Contains(out.String(), "- second") || !strings.Contains(out.String(), "trying to second: failed") {
+		t.Errorf("Output does not match: got %#v", out.String())
+	}
+}
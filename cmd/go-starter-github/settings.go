@@ -0,0 +1,133 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-github/github"
+	"net/http"
+)
+
+// Merge settings of pull requests, unset values are not changed
+type Merge struct {
+	AllowMergeCommit    *bool `yaml:"allow_merge_commit"`
+	AllowSquashMerge    *bool `yaml:"allow_squash_merge"`
+	AllowRebaseMerge    *bool `yaml:"allow_rebase_merge"`
+	DeleteBranchOnMerge *bool `yaml:"delete_branch_on_merge"`
+}
+
+// Team access to the repository
+type Team struct {
+	Name       string `yaml:"name"`
+	Permission string `yaml:"permission"`
+}
+
+// Label of issues and pull requests
+type Label struct {
+	Name        string `yaml:"name"`
+	Color       string `yaml:"color"`
+	Description string `yaml:"description"`
+}
+
+// RepositorySettings returns description, homepage and merge settings set in config, fields are named after GitHub API
+func RepositorySettings(cfg Config) map[string]interface{} {
+	settings := map[string]interface{}{}
+
+	if cfg.Description != "" {
+		settings["description"] = cfg.Description
+	}
+
+	if cfg.Homepage != "" {
+		settings["homepage"] = cfg.Homepage
+	}
+
+	if cfg.Merge.AllowMergeCommit != nil {
+		settings["allow_merge_commit"] = *cfg.Merge.AllowMergeCommit
+	}
+
+	if cfg.Merge.AllowSquashMerge != nil {
+		settings["allow_squash_merge"] = *cfg.Merge.AllowSquashMerge
+	}
+
+	if cfg.Merge.AllowRebaseMerge != nil {
+		settings["allow_rebase_merge"] = *cfg.Merge.AllowRebaseMerge
+	}
+
+	if cfg.Merge.DeleteBranchOnMerge != nil {
+		settings["delete_branch_on_merge"] = *cfg.Merge.DeleteBranchOnMerge
+	}
+
+	return settings
+}
+
+// EditRepository updates repository settings returned by RepositorySettings
+func EditRepository(cli *github.Client, owner, repo string, settings map[string]interface{}) error {
+	// go-github has no delete_branch_on_merge field, so request is built manually
+	req, err := cli.NewRequest("PATCH", fmt.Sprintf("repos/%v/%v", owner, repo), settings)
+	if err != nil {
+		return err
+	}
+
+	_, err = cli.Do(context.Background(), req, nil)
+	return err
+}
+
+// SetTopics of the repository, replacing existing ones
+func SetTopics(cli *github.Client, owner, repo string, topics []string) error {
+	_, _, err := cli.Repositories.ReplaceAllTopics(context.Background(), owner, repo, topics)
+	return err
+}
+
+// AddTeam grants organisation team access to the repository, team is referenced by its slug
+func AddTeam(cli *github.Client, org, owner, repo string, team Team) error {
+	perm := team.Permission
+	if perm == "" {
+		perm = "push"
+	}
+
+	req, err := cli.NewRequest("PUT", fmt.Sprintf("orgs/%v/teams/%v/repos/%v/%v", org, team.Name, owner, repo), map[string]string{
+		"permission": perm,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = cli.Do(context.Background(), req, nil)
+	return err
+}
+
+// CreateOrUpdateLabel in the repository
+func CreateOrUpdateLabel(cli *github.Client, owner, repo string, label Label) error {
+	l := &github.Label{Name: github.String(label.Name), Color: github.String(label.Color)}
+	if label.Description != "" {
+		l.Description = github.String(label.Description)
+	}
+
+	existing, _, err := cli.Issues.GetLabel(context.Background(), owner, repo, label.Name)
+	if e, ok := err.(*github.ErrorResponse); ok && e.Response != nil && e.Response.StatusCode == http.StatusNotFound {
+		_, _, err = cli.Issues.CreateLabel(context.Background(), owner, repo, l)
+		return err
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if existing.GetColor() == label.Color && (label.Description == "" || existing.GetDescription() == label.Description) {
+		return nil
+	}
+
+	_, _, err = cli.Issues.EditLabel(context.Background(), owner, repo, label.Name, l)
+	return err
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSettings(t *testing.T) {
+	server, cli := newFakeGitHub(t, map[string]response{
+		"GET /api/v3/repos/adobe/awesome/labels/bug":                  {body: `{"name":"bug","color":"ffffff"}`},
+		"PATCH /api/v3/repos/adobe/awesome":                           {},
+		"PUT /api/v3/repos/adobe/awesome/topics":                      {},
+		"PUT /api/v3/orgs/adobe/teams/developers/repos/adobe/awesome": {},
+		"PATCH /api/v3/repos/adobe/awesome/labels/bug":                {},
+		"POST /api/v3/repos/adobe/awesome/labels":                     {},
+	})
+
+	defer server.Close()
+
+	cfg := Config{
+		Description: "Awesome project",
+		Merge:       Merge{AllowMergeCommit: BoolPtr(false), DeleteBranchOnMerge: BoolPtr(true)},
+	}
+
+	if err := EditRepository(cli, "adobe", "awesome", RepositorySettings(cfg)); err != nil {
+		t.Fatalf("EditRepository() failed: %v", err)
+	}
+
+	if settings := RepositorySettings(Config{}); len(settings) != 0 {
+		t.Errorf("Settings of empty config must be empty: got %#v", settings)
+	}
+
+	if err := SetTopics(cli, "adobe", "awesome", []string{"go", "starter"}); err != nil {
+		t.Fatalf("SetTopics() failed: %v", err)
+	}
+
+	if err := AddTeam(cli, "adobe", "adobe", "awesome", Team{Name: "developers"}); err != nil {
+		t.Fatalf("AddTeam() failed: %v", err)
+	}
+
+	if err := CreateOrUpdateLabel(cli, "adobe", "awesome", Label{Name: "bug", Color: "d73a4a"}); err != nil {
+		t.Fatalf("CreateOrUpdateLabel() failed: %v", err)
+	}
+
+	if err := CreateOrUpdateLabel(cli, "adobe", "awesome", Label{Name: "feature", Color: "a2eeef", Description: "New feature"}); err != nil {
+		t.Fatalf("CreateOrUpdateLabel() failed: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"PATCH /api/v3/repos/adobe/awesome": map[string]interface{}{
+			"description":            "Awesome project",
+			"allow_merge_commit":     false,
+			"delete_branch_on_merge": true,
+		},
+		"PUT /api/v3/repos/adobe/awesome/topics": map[string]interface{}{
+			"names": []interface{}{"go", "starter"},
+		},
+		"PUT /api/v3/orgs/adobe/teams/developers/repos/adobe/awesome": map[string]interface{}{
+			"permission": "push",
+		},
+		"PATCH /api/v3/repos/adobe/awesome/labels/bug": map[string]interface{}{
+			"name":  "bug",
+			"color": "d73a4a",
+		},
+		"POST /api/v3/repos/adobe/awesome/labels": map[string]interface{}{
+			"name":        "feature",
+			"color":       "a2eeef",
+			"description": "New feature",
+		},
+	}
+
+	if got := server.requests; !reflect.DeepEqual(got, want) {
+		t.Errorf("Requests do not match: got %#v, want %#v", got, want)
+	}
+}
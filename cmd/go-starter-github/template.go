@@ -0,0 +1,130 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-github/github"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ParseRepository splits owner/name reference of GitHub repository
+func ParseRepository(ref string) (string, string, error) {
+	parts := strings.Split(ref, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid repository %#v, must be in owner/name format", ref)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// GenerateFromTemplate creates repository from GitHub template repository, owner is organisation or user
+func GenerateFromTemplate(cli *github.Client, template, owner, name string, private bool) (*github.Repository, error) {
+	tplOwner, tplName, err := ParseRepository(template)
+	if err != nil {
+		return nil, err
+	}
+
+	// go-github has no template API, so request is built manually
+	req, err := cli.NewRequest("POST", fmt.Sprintf("repos/%v/%v/generate", tplOwner, tplName), map[string]interface{}{
+		"owner":   owner,
+		"name":    name,
+		"private": private,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github.baptiste-preview+json")
+
+	repo := new(github.Repository)
+	if _, err := cli.Do(context.Background(), req, repo); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// Fork creates fork of upstream repository with a given name, org is empty to fork into current account. Fork is
+// created asynchronously, so its content may be unavailable for a while.
+func Fork(cli *github.Client, upstream, org, name string) (*github.Repository, error) {
+	upOwner, upName, err := ParseRepository(upstream)
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]string{"name": name}
+	if org != "" {
+		body["organization"] = org
+	}
+
+	req, err := cli.NewRequest("POST", fmt.Sprintf("repos/%v/%v/forks", upOwner, upName), body)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := new(github.Repository)
+	if _, err := cli.Do(context.Background(), req, repo); err != nil {
+		if _, ok := err.(*github.AcceptedError); !ok {
+			return nil, err
+		}
+	}
+
+	return repo, nil
+}
+
+// fetchAttempts and fetchDelay control waiting for content of repository created from template or fork
+var fetchAttempts, fetchDelay = 10, 3 * time.Second
+
+// CommitOnTop commits local files on top of the base branch of remote repository, so history of template or upstream
+// repository is kept. Local repository with the remote must exist already. Starter files are removed but recorded answers are kept so project can be upgraded later.
+func CommitOnTop(env []string, remote, base, branch string) error {
+	// content of the repository is copied in background, so it could be unavailable right after creation
+	var err error
+	for i := 0; i < fetchAttempts; i++ {
+		if i > 0 {
+			time.Sleep(fetchDelay)
+		}
+
+		if err = runWithEnv(env, "git", "fetch", remote, base); err == nil {
+			break
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to fetch %#v branch: %v", base, err)
+	}
+
+	commands := [][]string{
+		{"symbolic-ref", "HEAD", "refs/heads/" + branch},
+		{"reset", "--mixed", "FETCH_HEAD"},
+		{"add", "-A"},
+		{"rm", "-r", "--cached", "--quiet", "--ignore-unmatch", ".starter", ".starter.yml"},
+	}
+
+	for _, args := range commands {
+		if err := run("git", args...); err != nil {
+			return err
+		}
+	}
+
+	// project could be identical to the template
+	if err := exec.Command("git", "diff", "--cached", "--quiet").Run(); err == nil {
+		return nil
+	}
+
+	return run("git", "commit", "-m", "Apply go-starter answers")
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseRepository(t *testing.T) {
+	tests := []struct {
+		ref   string
+		owner string
+		name  string
+		err   bool
+	}{
+		{"adobe/go-starter", "adobe", "go-starter", false},
+		{"adobe", "", "", true},
+		{"adobe/", "", "", true},
+		{"adobe/go-starter/master", "", "", true},
+	}
+
+	for _, test := range tests {
+		owner, name, err := ParseRepository(test.ref)
+		if (err != nil) != test.err || owner != test.owner || name != test.name {
+			t.Errorf("ParseRepository(%#v) does not match: got %#v, %#v, %v", test.ref, owner, name, err)
+		}
+	}
+}
+
+func TestGenerateAndFork(t *testing.T) {
+	server, cli := newFakeGitHub(t, map[string]response{
+		"POST /api/v3/repos/adobe/go-template/generate": {status: http.StatusCreated, body: `{"name":"awesome","default_branch":"main"}`},
+		"POST /api/v3/repos/adobe/go-starter/forks":     {status: http.StatusAccepted, body: `{"name":"awesome","default_branch":"master"}`},
+	})
+
+	defer server.Close()
+
+	repo, err := GenerateFromTemplate(cli, "adobe/go-template", "octocat", "awesome", true)
+	if err != nil {
+		t.Fatalf("GenerateFromTemplate() failed: %v", err)
+	}
+
+	if repo.GetDefaultBranch() != "main" {
+		t.Errorf("Default branch does not match: got %#v, want %#v", repo.GetDefaultBranch(), "main")
+	}
+
+	repo, err = Fork(cli, "adobe/go-starter", "", "awesome")
+	if err != nil {
+		t.Fatalf("Fork() failed: %v", err)
+	}
+
+	if repo.GetName() != "awesome" {
+		t.Errorf("Name does not match: got %#v, want %#v", repo.GetName(), "awesome")
+	}
+
+	want := map[string]interface{}{
+		"POST /api/v3/repos/adobe/go-template/generate": map[string]interface{}{"owner": "octocat", "name": "awesome", "private": true},
+		"POST /api/v3/repos/adobe/go-starter/forks":     map[string]interface{}{"name": "awesome"},
+	}
+
+	if got := server.requests; !reflect.DeepEqual(got, want) {
+		t.Errorf("Requests do not match: got %#v, want %#v", got, want)
+	}
+}
+
+func TestCommitOnTop(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-starter-github")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"GIT_AUTHOR_NAME", "GIT_AUTHOR_EMAIL", "GIT_COMMITTER_NAME", "GIT_COMMITTER_EMAIL"} {
+		defer os.Setenv(name, os.Getenv(name))
+		os.Setenv(name, "go-starter")
+	}
+
+	git := func(dir string, args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+
+		return string(out)
+	}
+
+	// template repository with a single commit
+	remote, template, project := filepath.Join(dir, "remote.git"), filepath.Join(dir, "template"), filepath.Join(dir, "project")
+
+	git(dir, "init", "--bare", remote)
+	git(dir, "init", template)
+	_ = ioutil.WriteFile(filepath.Join(template, "README.md"), []byte("template"), 0644)
+	_ = ioutil.WriteFile(filepath.Join(template, ".starter.yml"), []byte("questions: []"), 0644)
+	git(template, "add", "-A")
+	git(template, "commit", "-m", "Template")
+	git(template, "push", remote, "HEAD:refs/heads/main")
+
+	// generated project
+	_ = os.Mkdir(project, 0755)
+	_ = ioutil.WriteFile(filepath.Join(project, "README.md"), []byte("awesome"), 0644)
+	_ = ioutil.WriteFile(filepath.Join(project, ".starter.yml"), []byte("questions: []"), 0644)
+
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+
+	if err := os.Chdir(project); err != nil {
+		t.Fatal(err)
+	}
+
+	git(project, "init")
+	git(project, "remote", "add", "upstream", remote)
+
+	defer func(n int) { fetchAttempts = n }(fetchAttempts)
+
+	fetchAttempts = 1
+	if err := CommitOnTop(nil, "upstream", "main", "develop"); err != nil {
+		t.Fatalf("CommitOnTop() failed: %v", err)
+	}
+
+	if log := git(project, "log", "--format=%s", "develop"); log != "Apply go-starter answers\nTemplate\n" {
+		t.Errorf("History does not match: got %#v", log)
+	}
+
+	if files := git(project, "ls-files"); files != "README.md\n" {
+		t.Errorf("Committed files do not match: got %#v", files)
+	}
+
+	if err := CommitOnTop(nil, "upstream", "missing", "develop"); err == nil {
+		t.Errorf("CommitOnTop() with missing branch must fail")
+	}
+}
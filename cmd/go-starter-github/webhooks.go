@@ -0,0 +1,144 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/adobe/go-starter/pkg/keychainx"
+	"github.com/google/go-github/github"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Webhook of the repository
+type Webhook struct {
+	URL string `yaml:"url"`
+	// ContentType is json (default) or form
+	ContentType string `yaml:"content_type"`
+	// Shared secret is read from a file or keychain item with a given label
+	SecretFile     string `yaml:"secret_file"`
+	SecretKeychain string `yaml:"secret_keychain"`
+	// Events triggering the webhook, default is push
+	Events []string `yaml:"events"`
+}
+
+// Secret reads shared secret of the webhook, empty string is returned when webhook has no secret
+func (w Webhook) Secret() (string, error) {
+	switch {
+	case w.SecretFile != "":
+		data, err := ioutil.ReadFile(w.SecretFile)
+		if err != nil {
+			return "", err
+		}
+
+		return strings.TrimSpace(string(data)), nil
+	case w.SecretKeychain != "":
+		_, secret, err := keychainx.Load(w.SecretKeychain)
+		return secret, err
+	}
+
+	return "", nil
+}
+
+// Hook builds GitHub webhook with default values
+func (w Webhook) Hook(secret string) *github.Hook {
+	contentType := w.ContentType
+	if contentType == "" {
+		contentType = "json"
+	}
+
+	events := w.Events
+	if len(events) == 0 {
+		events = []string{"push"}
+	}
+
+	config := map[string]interface{}{
+		"url":          w.URL,
+		"content_type": contentType,
+	}
+
+	if secret != "" {
+		config["secret"] = secret
+	}
+
+	return &github.Hook{
+		Name:   github.String("web"),
+		Events: events,
+		Active: github.Bool(true),
+		Config: config,
+	}
+}
+
+// Webhooks returns existing webhooks of the repository by their URL
+func Webhooks(cli *github.Client, owner, repo string) (map[string]*github.Hook, error) {
+	hooks := map[string]*github.Hook{}
+
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		list, resp, err := cli.Repositories.ListHooks(context.Background(), owner, repo, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, h := range list {
+			if url, ok := h.Config["url"].(string); ok {
+				hooks[url] = h
+			}
+		}
+
+		if resp.NextPage == 0 {
+			return hooks, nil
+		}
+
+		opt.Page = resp.NextPage
+	}
+}
+
+// WebhookUpToDate checks if existing webhook matches desired one. Secret can't be read from GitHub, so webhook with a
+// secret is never up to date.
+func WebhookUpToDate(existing, desired *github.Hook) bool {
+	if _, ok := desired.Config["secret"]; ok {
+		return false
+	}
+
+	if existing.GetActive() != desired.GetActive() || existing.Config["content_type"] != desired.Config["content_type"] {
+		return false
+	}
+
+	have := append([]string(nil), existing.Events...)
+	want := append([]string(nil), desired.Events...)
+
+	sort.Strings(have)
+	sort.Strings(want)
+
+	return reflect.DeepEqual(have, want)
+}
+
+// CreateOrUpdateWebhook creates webhook or updates existing one when it's not nil
+func CreateOrUpdateWebhook(cli *github.Client, owner, repo string, existing, desired *github.Hook) error {
+	var err error
+
+	if existing == nil {
+		_, _, err = cli.Repositories.CreateHook(context.Background(), owner, repo, desired)
+	} else {
+		_, _, err = cli.Repositories.EditHook(context.Background(), owner, repo, existing.GetID(), desired)
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to configure webhook %v: %v", desired.Config["url"], err)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"github.com/google/go-github/github"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestWebhookUpToDate(t *testing.T) {
+	existing := &github.Hook{
+		Events: []string{"push", "pull_request"},
+		Active: BoolPtr(true),
+		Config: map[string]interface{}{"url": "https://example.com", "content_type": "json", "insecure_ssl": "0"},
+	}
+
+	tests := []struct {
+		webhook Webhook
+		secret  string
+		want    bool
+	}{
+		{Webhook{URL: "https://example.com", Events: []string{"pull_request", "push"}}, "", true},
+		{Webhook{URL: "https://example.com"}, "", false},
+		{Webhook{URL: "https://example.com", ContentType: "form", Events: []string{"push", "pull_request"}}, "", false},
+		{Webhook{URL: "https://example.com", Events: []string{"push", "pull_request"}}, "secret", false},
+	}
+
+	for _, test := range tests {
+		if got := WebhookUpToDate(existing, test.webhook.Hook(test.secret)); got != test.want {
+			t.Errorf("WebhookUpToDate(%#v) does not match: got %v, want %v", test.webhook, got, test.want)
+		}
+	}
+}
+
+func TestWebhooks(t *testing.T) {
+	server, cli := newFakeGitHub(t, map[string]response{
+		"GET /api/v3/repos/adobe/awesome/hooks":     {body: `[{"id":1,"events":["push"],"config":{"url":"https://chat.example.com"}}]`},
+		"POST /api/v3/repos/adobe/awesome/hooks":    {},
+		"PATCH /api/v3/repos/adobe/awesome/hooks/1": {},
+	})
+
+	defer server.Close()
+
+	hooks, err := Webhooks(cli, "adobe", "awesome")
+	if err != nil {
+		t.Fatalf("Webhooks() failed: %v", err)
+	}
+
+	if hooks["https://chat.example.com"].GetID() != 1 || len(hooks) != 1 {
+		t.Errorf("Webhooks do not match: got %#v", hooks)
+	}
+
+	file, err := ioutil.TempFile("", "go-starter-github")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(file.Name())
+
+	_, _ = file.WriteString("shared\n")
+	_ = file.Close()
+
+	webhook := Webhook{URL: "https://deploy.example.com", SecretFile: file.Name(), Events: []string{"deployment"}}
+
+	secret, err := webhook.Secret()
+	if err != nil {
+		t.Fatalf("Secret() failed: %v", err)
+	}
+
+	if err := CreateOrUpdateWebhook(cli, "adobe", "awesome", nil, webhook.Hook(secret)); err != nil {
+		t.Fatalf("CreateOrUpdateWebhook() failed: %v", err)
+	}
+
+	if err := CreateOrUpdateWebhook(cli, "adobe", "awesome", hooks["https://chat.example.com"], Webhook{URL: "https://chat.example.com"}.Hook("")); err != nil {
+		t.Fatalf("CreateOrUpdateWebhook() failed: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"POST /api/v3/repos/adobe/awesome/hooks": map[string]interface{}{
+			"name":   "web",
+			"events": []interface{}{"deployment"},
+			"active": true,
+			"config": map[string]interface{}{"url": "https://deploy.example.com", "content_type": "json", "secret": "shared"},
+		},
+		"PATCH /api/v3/repos/adobe/awesome/hooks/1": map[string]interface{}{
+			"name":   "web",
+			"events": []interface{}{"push"},
+			"active": true,
+			"config": map[string]interface{}{"url": "https://chat.example.com", "content_type": "json"},
+		},
+	}
+
+	if got := server.requests; !reflect.DeepEqual(got, want) {
+		t.Errorf("Requests do not match: got %#v, want %#v", got, want)
+	}
+}
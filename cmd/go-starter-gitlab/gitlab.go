@@ -0,0 +1,20 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+// AccessLevels of project members by their name, owner level can't be granted on projects
+var AccessLevels = map[string]int{
+	"guest":      10,
+	"reporter":   20,
+	"developer":  30,
+	"maintainer": 40,
+}
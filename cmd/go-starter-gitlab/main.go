@@ -0,0 +1,250 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/adobe/go-starter/pkg/auth"
+	"github.com/adobe/go-starter/pkg/console"
+	"github.com/adobe/go-starter/pkg/keychainx"
+	"github.com/adobe/go-starter/pkg/scm"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+var version, commit string
+
+func usage() {
+	out := flag.CommandLine.Output()
+	_, _ = fmt.Fprintf(out, "go-starter-gitlab version %v (commit %v)\n", version, commit)
+	_, _ = fmt.Fprintf(out, "\n")
+	_, _ = fmt.Fprintf(out, "Usage: %s [flags] <gitlab-namespace> <gitlab-project>\n", os.Args[0])
+	_, _ = fmt.Fprintf(out, "\nExample:\n")
+	_, _ = fmt.Fprintf(out, "    %s adobe/services awesome-project\n", os.Args[0])
+	_, _ = fmt.Fprintf(out, "\nFlags:\n")
+	flag.PrintDefaults()
+	_, _ = fmt.Fprintf(out, "\n")
+}
+
+func main() {
+	var host, remote, branch, visibility, description, deployKey, tokenFile, tokenVault string
+	var masked bool
+	var members, literalVariables, fileVariables, vaultVariables SliceFlag
+
+	flag.Usage = usage
+	flag.StringVar(&host, "host", hostDefault(), "GitLab host, use it for self-managed GitLab (eq. gitlab.example.com). Can be set with GITLAB_HOST environment variable")
+	flag.StringVar(&remote, "remote", "upstream", "Name of the remote in local repository")
+	flag.StringVar(&branch, "branch", "master", "Name of the master branch")
+	flag.StringVar(&visibility, "visibility", "private", "Visibility of the project: private, internal or public")
+	flag.StringVar(&description, "description", "", "Description of the project")
+	flag.StringVar(&deployKey, "deploy-key", "", "Add SSH deployment key to the project, add ':rw' suffix to grant write permissions to the key")
+	flag.Var(&members, "member", "Add members to the project by GitLab username. You can grant access level using following format: <username>:<level>. Level can be: guest, reporter, developer or maintainer, default is developer. Can be specified multiple times. Example: --member octocat:maintainer")
+	flag.Var(&literalVariables, "variable", "Create CI/CD variable from literal (eq. --variable=NAME=value)")
+	flag.Var(&fileVariables, "variable-file", "Create CI/CD variable from file (eq. --variable-file=NAME=./path/to/file)")
+	flag.Var(&vaultVariables, "variable-vault", "Create CI/CD variable from HashiCorp Vault KV secret (eq. --variable-vault=NAME=secret/path#field)")
+	flag.BoolVar(&masked, "masked", false, "Mask values of CI/CD variables in job logs, values must meet GitLab requirements for masked variables")
+	flag.StringVar(&tokenFile, "token-file", "", "Read GitLab personal token from a file")
+	flag.StringVar(&tokenVault, "token-vault", "", "Read GitLab personal token from HashiCorp Vault KV secret (eq. --token-vault=secret/path#field), Vault is configured with VAULT_ADDR and VAULT_TOKEN environment variables")
+	flag.Parse()
+
+	ui := console.New(os.Stdin, os.Stdout)
+
+	namespace, name := flag.Arg(0), flag.Arg(1)
+	if namespace == "" {
+		flag.Usage()
+		ui.Fatalf("GitLab namespace is empty\n")
+	}
+
+	if name == "" {
+		flag.Usage()
+		ui.Fatalf("GitLab project name is empty\n")
+	}
+
+	if visibility != "private" && visibility != "internal" && visibility != "public" {
+		ui.Fatalf("Unknown visibility %#v, must be one of: private, internal, public\n", visibility)
+	}
+
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+
+	u, err := url.Parse(host)
+	if err != nil || u.Host == "" {
+		ui.Fatalf("Invalid GitLab host %#v\n", host)
+	}
+
+	u = &url.URL{Scheme: u.Scheme, Host: u.Host}
+
+	// variables are read before project is created
+	variables, err := keychainx.ReadSecrets(literalVariables, fileVariables, vaultVariables)
+	if err != nil {
+		ui.Fatalf("An error occurred while reading variables: %v\n", err)
+	}
+
+	var key []byte
+	keyPath, keyPerm := SplitPermissions(deployKey, "ro")
+	if deployKey != "" {
+		if key, err = ioutil.ReadFile(keyPath); err != nil {
+			ui.Fatalf("Unable to read deployment key: %v\n", err)
+		}
+	}
+
+	credentials := auth.TokenChain{
+		UI:      ui,
+		Service: "GitLab",
+		Env:     "GITLAB_TOKEN",
+		File:    tokenFile,
+		Vault:   tokenVault,
+		Verify: func(user, pass string) error {
+			_, err := auth.VerifyGitLab(u, pass)
+			return err
+		},
+		Ask: func() (string, string) {
+			return auth.AskGitLab(ui, u)
+		},
+	}
+
+	// credentials are stored per host
+	_, token, err := credentials.Credentials(u.Host)
+	if err != nil {
+		ui.Fatalf("An error occurred while loading credentials: %v\n", err)
+	}
+
+	cli := scm.NewGitLab(u, token)
+
+	ns, err := cli.Namespace(namespace)
+	if err != nil {
+		ui.Fatalf("An error occurred while reading GitLab namespace %#v: %v\n", namespace, err)
+	}
+
+	// create project
+	project, err := cli.CreateProject(ns.ID, name, visibility, description)
+	if err != nil {
+		if err != scm.ErrExists {
+			ui.Fatalf("An error occurred while creating GitLab project: %v\n", err)
+		}
+
+		ui.Printf("Project %v/%v already exists, skipping project configuration...\n", namespace, name)
+		return
+	}
+
+	ui.Successf("New project created at %v\n", project.WebURL)
+
+	// init repository
+	if err := run("git", "init"); err != nil {
+		ui.Fatalf("An error occurred while running git init: %v\n", err)
+	}
+
+	// add all files
+	if err := run("git", "add", "-A"); err != nil {
+		ui.Fatalf("An error occurred while running git add: %v\n", err)
+	}
+
+	// remove starter files, but keep recorded answers so project can be upgraded later
+	if err := run("git", "rm", "-r", "--cached", "--ignore-unmatch", ".starter", ".starter.yml"); err != nil {
+		ui.Fatalf("An error occurred while running git rm: %v\n", err)
+	}
+
+	// commit
+	if err := run("git", "commit", "-m", "Initial commit"); err != nil {
+		ui.Fatalf("An error occurred while running git commit: %v\n", err)
+	}
+
+	if err := run("git", "remote", "add", remote, project.HTTPURLToRepo); err != nil {
+		ui.Fatalf("An error occurred while running git remote add: %v\n", err)
+	}
+
+	if err := run("git", "push", "--set-upstream", remote, branch); err != nil {
+		ui.Fatalf("An error occurred while running git push: %v\n", err)
+	}
+
+	for _, m := range members {
+		user, level := SplitPermissions(m, "developer")
+
+		access, ok := AccessLevels[level]
+		if !ok {
+			ui.Errorf("Unknown access level %#v of %#v member\n", level, user)
+			continue
+		}
+
+		id, err := cli.UserID(user)
+		if err == nil {
+			err = cli.AddMember(project.ID, id, access)
+		}
+
+		if err != nil {
+			ui.Errorf("An error occurred while adding %#v member: %v\n", m, err)
+		}
+	}
+
+	if deployKey != "" {
+		ui.Printf("Adding deployment key %#v with %#v permissions\n", keyPath, keyPerm)
+
+		if err := cli.AddDeployKey(project.ID, "Deploy Key", string(key), keyPerm == "rw"); err != nil {
+			ui.Errorf("An error occurred while adding %#v deployment key: %v\n", keyPath, err)
+		}
+	}
+
+	for _, v := range variables {
+		ui.Printf("Adding variable %#v...\n", v.Name)
+
+		if err := cli.SetVariable(project.ID, v.Name, v.Value, masked); err != nil {
+			ui.Errorf("An error occurred while adding variable: %v\n", err)
+		}
+	}
+}
+
+// hostDefault returns GitLab host from GITLAB_HOST environment variable or gitlab.com
+func hostDefault() string {
+	if host := os.Getenv("GITLAB_HOST"); host != "" {
+		return host
+	}
+
+	return auth.GitLabHost
+}
+
+// Run a cli command
+func run(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Stderr = os.Stderr
+	cmd.Stdout = os.Stdout
+	cmd.Stdin = os.Stdin
+
+	return cmd.Run()
+}
+
+func SplitPermissions(c, d string) (string, string) {
+	if parts := strings.SplitN(c, ":", 2); len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+
+	return c, d
+}
+
+type SliceFlag []string
+
+func (s *SliceFlag) Set(v string) error {
+	values := strings.Split(v, ",")
+	for _, v := range values {
+		*s = append(*s, strings.TrimSpace(v))
+	}
+
+	return nil
+}
+
+func (s *SliceFlag) String() string {
+	return strings.Join(*s, ",")
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// files larger than streamSize are processed line by line when engine supports it
+var streamSize int64 = 1 << 20
+
+// sniffSize is a number of bytes used to detect binary files, same as git uses
+const sniffSize = 8000
+
+// streamer renders content line by line, so large files are not loaded into memory
+type streamer interface {
+	stream(name string, r io.Reader, w io.Writer) (bool, error)
+}
+
+// skipped returns a reason why file should not be updated, or empty string if file should be updated
+func skipped(path string, file os.FileInfo) (string, error) {
+	if !file.Mode().IsRegular() {
+		return "not a regular file", nil
+	}
+
+	if maxSize > 0 && file.Size() > maxSize {
+		return fmt.Sprintf("file is larger than %v bytes", maxSize), nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+
+	defer f.Close()
+
+	head := make([]byte, sniffSize)
+
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+
+	if binary(head[:n]) {
+		return "binary file", nil
+	}
+
+	return "", nil
+}
+
+// binary tells if content looks like binary data: it contains NUL byte or has a known non-text MIME type
+func binary(head []byte) bool {
+	if bytes.IndexByte(head, 0) >= 0 {
+		return true
+	}
+
+	return !strings.HasPrefix(http.DetectContentType(head), "text/")
+}
+
+// updateStream updates file line by line, writing result into temporary file which replaces original file
+func updateStream(filename string, file os.FileInfo, s streamer) (bool, error) {
+	in, err := os.Open(filename)
+	if err != nil {
+		return false, err
+	}
+
+	defer in.Close()
+
+	out, err := ioutil.TempFile(filepath.Dir(filename), ".starter-replace-")
+	if err != nil {
+		return false, err
+	}
+
+	defer os.Remove(out.Name())
+
+	w := bufio.NewWriter(out)
+
+	changed, err := s.stream(filename, in, w)
+	if err == nil {
+		err = w.Flush()
+	}
+
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil || !changed {
+		return false, err
+	}
+
+	if err := os.Chmod(out.Name(), file.Mode().Perm()); err != nil {
+		return false, err
+	}
+
+	return true, os.Rename(out.Name(), filename)
+}
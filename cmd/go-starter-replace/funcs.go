@@ -0,0 +1,197 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"os"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// funcs available in templates rendered by gotemplate engine
+var funcs = template.FuncMap{
+	"upper":     strings.ToUpper,
+	"lower":     strings.ToLower,
+	"title":     title,
+	"pascal":    pascal,
+	"camel":     camel,
+	"snake":     snake,
+	"kebab":     kebab,
+	"pluralize": pluralize,
+	"trim":      strings.TrimSpace,
+	"replace":   func(old, new, s string) string { return strings.Replace(s, old, new, -1) },
+	"contains":  func(substr, s string) bool { return strings.Contains(s, substr) },
+	"hasPrefix": func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+	"hasSuffix": func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+	"split":     split,
+	"join":      func(sep string, items []string) string { return strings.Join(items, sep) },
+	"default":   defaultValue,
+	"env":       os.Getenv,
+}
+
+// modifiers which can be applied to placeholders by literal engine, eq. <NAME|pascal>
+var modifiers = map[string]func(string) string{
+	"upper":     strings.ToUpper,
+	"lower":     strings.ToLower,
+	"title":     title,
+	"pascal":    pascal,
+	"camel":     camel,
+	"snake":     snake,
+	"kebab":     kebab,
+	"pluralize": pluralize,
+}
+
+// words of the string, split by non-alphanumeric characters and case changes, eq. "myHTTPServer" is "my", "HTTP", "Server"
+func words(s string) []string {
+	var words []string
+	var word []rune
+
+	runes := []rune(s)
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(word) > 0 {
+				words, word = append(words, string(word)), nil
+			}
+
+			continue
+		}
+
+		if unicode.IsUpper(r) && len(word) > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				words, word = append(words, string(word)), nil
+			}
+		}
+
+		word = append(word, r)
+	}
+
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+
+	return words
+}
+
+// capitalize first letter of the word and lowercase the rest
+func capitalize(word string) string {
+	runes := []rune(strings.ToLower(word))
+	if len(runes) > 0 {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+
+	return string(runes)
+}
+
+// title - "my-service" becomes "My Service"
+func title(s string) string {
+	parts := words(s)
+	for i, w := range parts {
+		parts[i] = capitalize(w)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// pascal - "my-service" becomes "MyService"
+func pascal(s string) string {
+	parts := words(s)
+	for i, w := range parts {
+		parts[i] = capitalize(w)
+	}
+
+	return strings.Join(parts, "")
+}
+
+// camel - "my-service" becomes "myService"
+func camel(s string) string {
+	parts := words(s)
+	for i, w := range parts {
+		if i == 0 {
+			parts[i] = strings.ToLower(w)
+		} else {
+			parts[i] = capitalize(w)
+		}
+	}
+
+	return strings.Join(parts, "")
+}
+
+// snake - "my-service" becomes "my_service"
+func snake(s string) string {
+	return strings.ToLower(strings.Join(words(s), "_"))
+}
+
+// kebab - "my_service" becomes "my-service"
+func kebab(s string) string {
+	return strings.ToLower(strings.Join(words(s), "-"))
+}
+
+var irregular = map[string]string{
+	"child":  "children",
+	"person": "people",
+	"mouse":  "mice",
+}
+
+// pluralize english noun using basic rules, eq. "service" becomes "services", "policy" becomes "policies"
+func pluralize(s string) string {
+	lower := strings.ToLower(s)
+
+	for singular, plural := range irregular {
+		if strings.HasSuffix(lower, singular) {
+			return s[:len(s)-len(singular)] + matchCase(s[len(s)-len(singular):], plural)
+		}
+	}
+
+	switch {
+	case lower == "":
+		return s
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return s + matchCase(s, "es")
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsAny(lower[len(lower)-2:len(lower)-1], "aeiou"):
+		return s[:len(s)-1] + matchCase(s, "ies")
+	}
+
+	return s + matchCase(s, "s")
+}
+
+// matchCase makes suffix uppercase if original string is uppercase
+func matchCase(original, suffix string) string {
+	if original == strings.ToUpper(original) && original != strings.ToLower(original) {
+		return strings.ToUpper(suffix)
+	}
+
+	return suffix
+}
+
+// split string into list, eq. answer to multiselect question
+func split(sep, s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(s, sep)
+}
+
+// defaultValue returns value unless it's empty
+func defaultValue(d, value string) string {
+	if value == "" {
+		return d
+	}
+
+	return value
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import "testing"
+
+func TestCases(t *testing.T) {
+	tests := []struct {
+		input, pascal, camel, snake, kebab, title string
+	}{
+		{input: "my-service", pascal: "MyService", camel: "myService", snake: "my_service", kebab: "my-service", title: "My Service"},
+		{input: "my_service", pascal: "MyService", camel: "myService", snake: "my_service", kebab: "my-service", title: "My Service"},
+		{input: "MyService", pascal: "MyService", camel: "myService", snake: "my_service", kebab: "my-service", title: "My Service"},
+		{input: "myHTTPServer", pascal: "MyHttpServer", camel: "myHttpServer", snake: "my_http_server", kebab: "my-http-server", title: "My Http Server"},
+		{input: "MY_SERVICE", pascal: "MyService", camel: "myService", snake: "my_service", kebab: "my-service", title: "My Service"},
+		{input: "service v2", pascal: "ServiceV2", camel: "serviceV2", snake: "service_v2", kebab: "service-v2", title: "Service V2"},
+		{input: "", pascal: "", camel: "", snake: "", kebab: "", title: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			if got := pascal(test.input); got != test.pascal {
+				t.Errorf("pascal does not match: got %#v, want %#v", got, test.pascal)
+			}
+
+			if got := camel(test.input); got != test.camel {
+				t.Errorf("camel does not match: got %#v, want %#v", got, test.camel)
+			}
+
+			if got := snake(test.input); got != test.snake {
+				t.Errorf("snake does not match: got %#v, want %#v", got, test.snake)
+			}
+
+			if got := kebab(test.input); got != test.kebab {
+				t.Errorf("kebab does not match: got %#v, want %#v", got, test.kebab)
+			}
+
+			if got := title(test.input); got != test.title {
+				t.Errorf("title does not match: got %#v, want %#v", got, test.title)
+			}
+		})
+	}
+}
+
+func TestPluralize(t *testing.T) {
+	tests := map[string]string{
+		"service": "services",
+		"policy":  "policies",
+		"key":     "keys",
+		"box":     "boxes",
+		"match":   "matches",
+		"status":  "statuses",
+		"person":  "people",
+		"SERVICE": "SERVICES",
+		"":        "",
+	}
+
+	for input, want := range tests {
+		if got := pluralize(input); got != want {
+			t.Errorf("pluralize(%#v) does not match: got %#v, want %#v", input, got, want)
+		}
+	}
+}
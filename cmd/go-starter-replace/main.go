@@ -16,6 +16,10 @@ import (
 	"flag"
 	"fmt"
 	"github.com/adobe/go-starter/pkg/console"
+	"github.com/adobe/go-starter/pkg/diff"
+	"github.com/adobe/go-starter/pkg/ignore"
+	"github.com/adobe/go-starter/pkg/maker"
+	"gopkg.in/yaml.v2"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -23,9 +27,12 @@ import (
 )
 
 var version, commit string
-var skips = []string{".starter/", ".starter.yml", ".git/"}
+var skips = []string{"/.starter/", "/.starter.yml", "/.starter-answers.yml", "/.starterignore", "/.git/"}
 var prefix, suffix = "<", ">"
 var reverse bool
+var engine = "literal"
+var maxSize int64 = 10 << 20
+var dryRun, exitCode bool
 
 func usage() {
 	out := flag.CommandLine.Output()
@@ -44,48 +51,66 @@ func main() {
 	flag.StringVar(&prefix, "prefix", prefix, "Placeholder prefix")
 	flag.StringVar(&suffix, "suffix", suffix, "Placeholder suffix")
 	flag.BoolVar(&reverse, "reverse", reverse, "Replace values with placeholders (useful to revert changes made by go-starter-update)")
+	flag.Int64Var(&maxSize, "max-size", maxSize, "Maximum size of the file in bytes, larger files are skipped. Use 0 to disable the limit")
+	flag.StringVar(&engine, "engine", engine, "Template engine: literal (replace placeholders with values) or gotemplate (render files and their names using Go text/template)")
+	flag.BoolVar(&dryRun, "dry-run", dryRun, "Do not change files, print unified diff of changes and list of renames instead")
+	flag.BoolVar(&exitCode, "exit-code", exitCode, "Exit with code 1 if there are changes, useful with -dry-run to test templates")
 	flag.Parse()
 
-	replace(console.New(os.Stdin, os.Stdout), variables())
+	if changed := replace(console.New(os.Stdin, os.Stdout), variables()); changed && exitCode {
+		os.Exit(1)
+	}
 }
 
-func replace(ui *console.Console, vars map[string]string) {
+// replace placeholders in current folder, returns true if any file has been (or would be in dry-run mode) changed
+func replace(ui *console.Console, vars map[string]string) bool {
 	// check if .starter.yml exists to prevent running in wrong directory
 	if _, err := os.Stat(".starter.yml"); os.IsNotExist(err) {
 		ui.Errorf("Current folder does not look like template, .starter.yml does not exist\n")
-		return
+		return false
 	}
 
-	// create replace dictionary
-	dict := make(map[string]string)
-	for k, v := range vars {
-		key, val := prefix+k+suffix, v
-		if reverse {
-			key, val = val, key
-		}
+	r, err := newRenderer(engine, vars)
+	if err != nil {
+		ui.Fatalf("An error occurred: %v\n", err)
+	}
 
-		dict[key] = val
+	ignored, err := ignores()
+	if err != nil {
+		ui.Fatalf("An error occurred while reading ignore patterns: %v\n", err)
 	}
 
 	// list of paths to rename
 	var renames []string
+	var changed bool
 
 	// walk through current folder and update variables
-	err := filepath.Walk(".", func(path string, file os.FileInfo, err error) error {
+	err = filepath.Walk(".", func(path string, file os.FileInfo, err error) error {
 		if err != nil {
 			ui.Errorf("Unable to process path %#v: %v\n", path, err)
 			return nil
 		}
 
-		for _, skip := range skips {
-			if strings.HasPrefix(path, skip) {
-				return nil
+		if path == "." {
+			return nil
+		}
+
+		if ignored.Match(filepath.ToSlash(path), file.IsDir()) {
+			if file.IsDir() {
+				return filepath.SkipDir
 			}
+
+			return nil
 		}
 
 		name := file.Name()
 
-		if renamed := rename(name, dict); renamed != name {
+		renamed, err := rename(name, r)
+		if err != nil {
+			return err
+		}
+
+		if renamed != name {
 			renames = append(renames, path)
 		}
 
@@ -93,20 +118,65 @@ func replace(ui *console.Console, vars map[string]string) {
 			return nil
 		}
 
-		ok, err := update(path, dict)
+		reason, err := skipped(path, file)
+		if err != nil {
+			return err
+		}
+
+		if reason != "" {
+			ui.Debugf("Skipping %#v: %v\n", path, reason)
+			return nil
+		}
+
+		if dryRun {
+			diff, err := preview(path, r)
+			if err != nil {
+				return err
+			}
+
+			changed = changed || diff != ""
+			ui.Printf("%v", diff)
+
+			return nil
+		}
+
+		ok, err := update(path, file, r)
 		if err != nil {
 			return err
 		}
 
 		if ok {
+			changed = true
 			ui.Printf("Updating %#v\n", path)
 		}
 
 		return nil
 	})
 
-	for _, path := range renames {
-		renamed := rename(path, dict)
+	changed = changed || len(renames) > 0
+
+	// rename deepest paths first and only their base names, so renaming a directory does not affect its content
+	for i := len(renames) - 1; i >= 0; i-- {
+		path := renames[i]
+
+		renamed, err := rename(filepath.Base(path), r)
+		if err != nil {
+			ui.Errorf("Unable to rename path %#v: %v\n", path, err)
+			continue
+		}
+
+		renamed = filepath.Join(filepath.Dir(path), renamed)
+
+		if dryRun {
+			planned, err := renameAll(path, r)
+			if err != nil {
+				ui.Errorf("Unable to rename path %#v: %v\n", path, err)
+				continue
+			}
+
+			ui.Printf("Would rename %#v to %#v\n", path, planned)
+			continue
+		}
 
 		ui.Printf("Renaming %#v to %#v\n", path, renamed)
 		if err := os.Rename(path, renamed); err != nil {
@@ -117,6 +187,36 @@ func replace(ui *console.Console, vars map[string]string) {
 	if err != nil {
 		ui.Fatalf("An error occurred while traversing file system: %v\n", err)
 	}
+
+	return changed
+}
+
+// ignores from .starterignore and ignore section of .starter.yml
+func ignores() (*ignore.Matcher, error) {
+	m, err := ignore.New(skips...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.Load(".starterignore"); err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(".starter.yml")
+	if err != nil {
+		return nil, err
+	}
+
+	var config maker.Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("unable to parse .starter.yml: %v", err)
+	}
+
+	if err := m.Add(config.Ignore...); err != nil {
+		return nil, fmt.Errorf("unable to parse ignore section of .starter.yml: %v", err)
+	}
+
+	return m, nil
 }
 
 // variables from environment
@@ -143,32 +243,68 @@ func variables() map[string]string {
 }
 
 // rename - update placeholders in file name
-func rename(filename string, params map[string]string) string {
-	for k, v := range params {
-		filename = strings.Replace(filename, k, v, -1)
+func rename(filename string, r renderer) (string, error) {
+	output, err := r.render(filename, []byte(filename))
+	if err != nil {
+		return filename, err
+	}
+
+	return string(output), nil
+}
+
+// renameAll updates placeholders in every element of the path, eq. final path after all renames
+func renameAll(path string, r renderer) (string, error) {
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	for i, part := range parts {
+		renamed, err := rename(part, r)
+		if err != nil {
+			return path, err
+		}
+
+		parts[i] = renamed
 	}
 
-	return filename
+	return filepath.FromSlash(strings.Join(parts, "/")), nil
 }
 
-// update placeholders in file
-func update(filename string, params map[string]string) (bool, error) {
+// preview changes of the file as unified diff
+func preview(filename string, r renderer) (string, error) {
 	input, err := ioutil.ReadFile(filename)
 	if err != nil {
-		return false, err
+		return "", err
 	}
 
-	output := input
+	output, err := r.render(filename, input)
+	if err != nil {
+		return "", err
+	}
+
+	name := filepath.ToSlash(filename)
+
+	return diff.Unified("a/"+name, "b/"+name, string(input), string(output), 3), nil
+}
 
-	for k, v := range params {
-		output = bytes.Replace(output, []byte(k), []byte(v), -1)
+// update placeholders in file, preserving its permissions
+func update(filename string, file os.FileInfo, r renderer) (bool, error) {
+	if s, ok := r.(streamer); ok && file.Size() > streamSize {
+		return updateStream(filename, file, s)
+	}
+
+	input, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return false, err
+	}
+
+	output, err := r.render(filename, input)
+	if err != nil {
+		return false, err
 	}
 
 	if bytes.Equal(input, output) {
 		return false, nil
 	}
 
-	if err = ioutil.WriteFile(filename, output, 0666); err != nil {
+	if err = ioutil.WriteFile(filename, output, file.Mode().Perm()); err != nil {
 		return false, err
 	}
 
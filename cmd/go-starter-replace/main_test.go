@@ -49,6 +49,26 @@ func TestReplace(t *testing.T) {
 	assert(t, "nested/REPLACED/file.txt", "foo REPLACED bar")
 }
 
+// Test placeholders with modifiers in file contents and names
+func TestReplaceModifiers(t *testing.T) {
+	teardown := setup(t)
+	defer teardown()
+
+	create(t, ".starter.yml", "")
+	create(t, "file.txt", "<NAME> <NAME|pascal> <NAME|camel> <NAME|snake> <NAME|kebab> <NAME|upper> <NAME|snake|upper> <NAME_FULL|title>")
+	create(t, "<NAME|snake>/<NAME|pascal>.go", "package <NAME|snake>")
+
+	ui := console.New(bytes.NewBuffer(nil), ioutil.Discard)
+
+	replace(ui, map[string]string{
+		"NAME":      "my-service",
+		"NAME_FULL": "my awesome service",
+	})
+
+	assert(t, "file.txt", "my-service MyService myService my_service my-service MY-SERVICE MY_SERVICE My Awesome Service")
+	assert(t, "my_service/MyService.go", "package my_service")
+}
+
 // Test reverse replace: values back to placeholders
 func TestReplaceReverse(t *testing.T) {
 	teardown := setup(t)
@@ -72,6 +92,167 @@ func TestReplaceReverse(t *testing.T) {
 	assert(t, "nested/<PLACEHOLDER>/file.txt", "foo <PLACEHOLDER> bar")
 }
 
+// Test rendering files and their names with Go templates
+func TestReplaceGoTemplate(t *testing.T) {
+	teardown := setup(t)
+	defer teardown()
+
+	create(t, ".starter.yml", "")
+	create(t, "file.txt", "type {{ .APPLICATION_NAME | pascal }} struct{}")
+	create(t, "plain.txt", "foo <APPLICATION_NAME> bar")
+	create(t, "{{ .application_name | snake }}/file.txt", `{{ range split "," .FEATURES }}{{ . | upper }};{{ end }}`)
+	create(t, "defaults.txt", `{{ default "none" .UNDEFINED }}`)
+
+	engine = "gotemplate"
+
+	ui := console.New(bytes.NewBuffer(nil), ioutil.Discard)
+
+	replace(ui, map[string]string{
+		"APPLICATION_NAME": "awesome-project",
+		"FEATURES":         "metrics,grpc",
+	})
+
+	assert(t, "file.txt", "type AwesomeProject struct{}")
+	assert(t, "plain.txt", "foo <APPLICATION_NAME> bar")
+	assert(t, "awesome_project/file.txt", "METRICS;GRPC;")
+	assert(t, "defaults.txt", "none")
+}
+
+// Test binary files and files over the size limit are not updated, but still renamed
+func TestReplaceSkipsBinaryAndLargeFiles(t *testing.T) {
+	teardown := setup(t)
+	defer teardown()
+
+	png := "\x89PNG\r\n\x1a\n<PLACEHOLDER>"
+
+	create(t, ".starter.yml", "")
+	create(t, "binary.dat", "foo\x00<PLACEHOLDER>")
+	create(t, "image-<PLACEHOLDER>.png", png)
+	create(t, "large.txt", "foo <PLACEHOLDER> bar"+strings.Repeat(".", 100))
+
+	maxSize = 100
+
+	ui := console.New(bytes.NewBuffer(nil), ioutil.Discard)
+
+	replace(ui, map[string]string{
+		"PLACEHOLDER": "REPLACED",
+	})
+
+	assert(t, "binary.dat", "foo\x00<PLACEHOLDER>")
+	assert(t, "image-REPLACED.png", png)
+	assert(t, "large.txt", "foo <PLACEHOLDER> bar"+strings.Repeat(".", 100))
+}
+
+// Test large files are streamed and file permissions are preserved
+func TestReplaceStreamsAndPreservesMode(t *testing.T) {
+	teardown := setup(t)
+	defer teardown()
+
+	create(t, ".starter.yml", "")
+	create(t, "script.sh", "#!/bin/sh\necho <PLACEHOLDER>\n")
+	create(t, "stream.sh", "#!/bin/sh\necho <PLACEHOLDER>\necho <PLACEHOLDER|upper>")
+
+	for _, file := range []string{"script.sh", "stream.sh"} {
+		if err := os.Chmod(file, 0750); err != nil {
+			t.Fatalf("Unable to change test file mode: %v", err)
+		}
+	}
+
+	streamSize = 20
+
+	ui := console.New(bytes.NewBuffer(nil), ioutil.Discard)
+
+	replace(ui, map[string]string{
+		"PLACEHOLDER": "replaced",
+	})
+
+	assert(t, "script.sh", "#!/bin/sh\necho replaced\n")
+	assert(t, "stream.sh", "#!/bin/sh\necho replaced\necho REPLACED")
+
+	for _, file := range []string{"script.sh", "stream.sh"} {
+		info, err := os.Stat(file)
+		if err != nil {
+			t.Fatalf("Unable to stat test file: %v", err)
+		}
+
+		if got, want := info.Mode().Perm(), os.FileMode(0750); got != want {
+			t.Errorf("File %v mode does not match: got %v, want %v", file, got, want)
+		}
+	}
+}
+
+// Test paths listed in .starterignore and .starter.yml are not updated or renamed
+func TestReplaceIgnore(t *testing.T) {
+	teardown := setup(t)
+	defer teardown()
+
+	create(t, ".starter.yml", "ignore:\n  - fixtures/\n")
+	create(t, ".starterignore", "# vendored code\nvendor/\n*.golden\n!keep.golden\n")
+	create(t, "file.txt", "foo <PLACEHOLDER> bar")
+	create(t, "vendor/<PLACEHOLDER>/file.txt", "foo <PLACEHOLDER> bar")
+	create(t, "fixtures/file.txt", "foo <PLACEHOLDER> bar")
+	create(t, "testdata/output.golden", "foo <PLACEHOLDER> bar")
+	create(t, "testdata/keep.golden", "foo <PLACEHOLDER> bar")
+
+	ui := console.New(bytes.NewBuffer(nil), ioutil.Discard)
+
+	replace(ui, map[string]string{
+		"PLACEHOLDER": "REPLACED",
+	})
+
+	assert(t, "file.txt", "foo REPLACED bar")
+	assert(t, "vendor/<PLACEHOLDER>/file.txt", "foo <PLACEHOLDER> bar")
+	assert(t, "fixtures/file.txt", "foo <PLACEHOLDER> bar")
+	assert(t, "testdata/output.golden", "foo <PLACEHOLDER> bar")
+	assert(t, "testdata/keep.golden", "foo REPLACED bar")
+	assert(t, ".starterignore", "# vendored code\nvendor/\n*.golden\n!keep.golden\n")
+}
+
+// Test dry-run prints planned changes without touching files
+func TestReplaceDryRun(t *testing.T) {
+	teardown := setup(t)
+	defer teardown()
+
+	create(t, ".starter.yml", "")
+	create(t, "file.txt", "foo\n<PLACEHOLDER>\nbar\n")
+	create(t, "<PLACEHOLDER>/<PLACEHOLDER>.txt", "foo")
+
+	dryRun = true
+
+	out := bytes.NewBuffer(nil)
+	ui := console.New(bytes.NewBuffer(nil), out)
+
+	changed := replace(ui, map[string]string{
+		"PLACEHOLDER": "REPLACED",
+	})
+
+	if !changed {
+		t.Errorf("Dry-run should report changes")
+	}
+
+	assert(t, "file.txt", "foo\n<PLACEHOLDER>\nbar\n")
+	assert(t, "<PLACEHOLDER>/<PLACEHOLDER>.txt", "foo")
+
+	for _, want := range []string{
+		"--- a/file.txt\n+++ b/file.txt\n@@ -1,3 +1,3 @@\n foo\n-<PLACEHOLDER>\n+REPLACED\n bar\n",
+		`Would rename "<PLACEHOLDER>" to "REPLACED"`,
+		`Would rename "<PLACEHOLDER>/<PLACEHOLDER>.txt" to "REPLACED/REPLACED.txt"`,
+	} {
+		if got := out.String(); !strings.Contains(got, want) {
+			t.Errorf("Output should contain %#v, got %v", want, got)
+		}
+	}
+
+	// no changes are reported once template is applied
+	dryRun = false
+	replace(ui, map[string]string{"PLACEHOLDER": "REPLACED"})
+
+	dryRun = true
+	if replace(ui, map[string]string{"PLACEHOLDER": "REPLACED"}) {
+		t.Errorf("Dry-run should not report changes when there is nothing to replace")
+	}
+}
+
 // Make sure go-starter-replace won't run in non-template directory
 func TestReplaceChecksStarterConfig(t *testing.T) {
 	teardown := setup(t)
@@ -117,7 +298,9 @@ func setup(t *testing.T) func() {
 		_ = os.Chdir(cwd)
 
 		// reset flags
-		prefix, suffix, reverse = "<", ">", false
+		prefix, suffix, reverse, engine = "<", ">", false, "literal"
+		maxSize, streamSize = 10<<20, 1<<20
+		dryRun, exitCode = false, false
 
 		// remove test workspace
 		if err := os.RemoveAll(path); err != nil {
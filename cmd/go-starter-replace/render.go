@@ -0,0 +1,174 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// renderer turns content of template file (or its name) into content of project file
+type renderer interface {
+	render(name string, content []byte) ([]byte, error)
+}
+
+// newRenderer for given engine
+func newRenderer(engine string, vars map[string]string) (renderer, error) {
+	switch engine {
+	case "literal":
+		return newLiteral(vars), nil
+	case "gotemplate":
+		if reverse {
+			return nil, fmt.Errorf("reverse mode is not supported by gotemplate engine")
+		}
+
+		return newGoTemplate(vars), nil
+	}
+
+	return nil, fmt.Errorf("unknown template engine %#v, use literal or gotemplate", engine)
+}
+
+// literal renderer replaces placeholders with values, placeholders may have modifiers, eq. <NAME|snake|upper>
+type literal struct {
+	dict      map[string]string
+	vars      map[string]string
+	modifiers *regexp.Regexp
+}
+
+func newLiteral(vars map[string]string) literal {
+	l := literal{dict: make(map[string]string), vars: vars}
+
+	var names []string
+	for k, v := range vars {
+		key, val := prefix+k+suffix, v
+		if reverse {
+			key, val = val, key
+		}
+
+		l.dict[key] = val
+		names = append(names, regexp.QuoteMeta(k))
+	}
+
+	// modifiers can not be reverted, because different values may produce the same result
+	if reverse || len(names) == 0 {
+		return l
+	}
+
+	// prefer longer names, so NAME_FULL is not matched as NAME
+	sort.Slice(names, func(i, j int) bool {
+		return len(names[i]) > len(names[j])
+	})
+
+	l.modifiers = regexp.MustCompile(regexp.QuoteMeta(prefix) + `(` + strings.Join(names, "|") + `)((?:\|[a-z]+)+)` + regexp.QuoteMeta(suffix))
+
+	return l
+}
+
+func (l literal) render(name string, content []byte) ([]byte, error) {
+	var err error
+
+	if l.modifiers != nil {
+		content = l.modifiers.ReplaceAllFunc(content, func(match []byte) []byte {
+			groups := l.modifiers.FindSubmatch(match)
+
+			value := l.vars[string(groups[1])]
+			for _, m := range strings.Split(string(groups[2][1:]), "|") {
+				modifier, ok := modifiers[m]
+				if !ok {
+					err = fmt.Errorf("unknown modifier %#v in placeholder %v of %#v", m, string(match), name)
+					return match
+				}
+
+				value = modifier(value)
+			}
+
+			return []byte(value)
+		})
+	}
+
+	for k, v := range l.dict {
+		content = bytes.Replace(content, []byte(k), []byte(v), -1)
+	}
+
+	return content, err
+}
+
+func (l literal) stream(name string, r io.Reader, w io.Writer) (bool, error) {
+	changed := false
+	reader := bufio.NewReader(r)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return false, err
+		}
+
+		output, rerr := l.render(name, line)
+		if rerr != nil {
+			return false, rerr
+		}
+
+		changed = changed || !bytes.Equal(line, output)
+
+		if _, werr := w.Write(output); werr != nil {
+			return false, werr
+		}
+
+		if err == io.EOF {
+			return changed, nil
+		}
+	}
+}
+
+// gotemplate renderer executes content as Go text/template with variables as data
+type gotemplate struct {
+	data map[string]string
+}
+
+func newGoTemplate(vars map[string]string) gotemplate {
+	data := make(map[string]string)
+	for k, v := range vars {
+		// variables are available by their original (uppercase) and lowercase names
+		data[strings.ToLower(k)] = v
+	}
+
+	for k, v := range vars {
+		data[k] = v
+	}
+
+	return gotemplate{data: data}
+}
+
+func (g gotemplate) render(name string, content []byte) ([]byte, error) {
+	// skip parsing content which has no actions
+	if !bytes.Contains(content, []byte("{{")) {
+		return content, nil
+	}
+
+	tpl, err := template.New(name).Funcs(funcs).Option("missingkey=zero").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse template %#v: %v", name, err)
+	}
+
+	out := bytes.NewBuffer(nil)
+	if err := tpl.Execute(out, g.data); err != nil {
+		return nil, fmt.Errorf("unable to render template %#v: %v", name, err)
+	}
+
+	return out.Bytes(), nil
+}
@@ -0,0 +1,289 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/adobe/go-starter/pkg/auth"
+	"github.com/adobe/go-starter/pkg/console"
+	"github.com/adobe/go-starter/pkg/keychainx"
+	"github.com/adobe/go-starter/pkg/scm"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+var version, commit string
+
+func usage() {
+	out := flag.CommandLine.Output()
+	_, _ = fmt.Fprintf(out, "go-starter-scm version %v (commit %v)\n", version, commit)
+	_, _ = fmt.Fprintf(out, "\n")
+	_, _ = fmt.Fprintf(out, "Usage: %s -provider <github|gitea|bitbucket> [flags] <owner> <repo>\n", os.Args[0])
+	_, _ = fmt.Fprintf(out, "\nOwner is organisation or user on GitHub and Gitea, project key on Bitbucket Server (~username for personal projects).\n")
+	_, _ = fmt.Fprintf(out, "\nExample:\n")
+	_, _ = fmt.Fprintf(out, "    %s -provider gitea -host gitea.example.com adobe awesome-project\n", os.Args[0])
+	_, _ = fmt.Fprintf(out, "\nFlags:\n")
+	flag.PrintDefaults()
+	_, _ = fmt.Fprintf(out, "\n")
+}
+
+// tokenEnv is environment variable with token of each provider
+var tokenEnv = map[string]string{
+	"github":    "GITHUB_TOKEN",
+	"gitea":     "GITEA_TOKEN",
+	"bitbucket": "BITBUCKET_TOKEN",
+}
+
+func main() {
+	var provider, host, remote, branch, deployKey, tokenFile, tokenVault string
+	var public bool
+	var collaborators, literalSecrets, fileSecrets, vaultSecrets SliceFlag
+	var protection scm.Protection
+
+	flag.Usage = usage
+	flag.StringVar(&provider, "provider", "", "Provider of repositories: github, gitea or bitbucket (Bitbucket Server)")
+	flag.StringVar(&host, "host", "", "Host of the provider with optional context path (eq. bitbucket.example.com/bitbucket), default is github.com for GitHub")
+	flag.StringVar(&remote, "remote", "upstream", "Name of the remote in local repository")
+	flag.StringVar(&branch, "branch", "master", "Name of the master branch")
+	flag.BoolVar(&public, "public", false, "Make repository public")
+	flag.StringVar(&deployKey, "deploy-key", "", "Add SSH deployment key to the repository, add ':rw' suffix to grant write permissions to the key")
+	flag.Var(&collaborators, "collaborator", "Add collaborators to the repository by username. You can grant permissions using following format: <username>:<permission>. Permission can be: read, write or admin, default is write. Can be specified multiple times. Example: --collaborator octocat:read")
+	flag.Var(&literalSecrets, "secret-literal", "Create CI secret from literal (eq. --secret-literal=secret_name=value)")
+	flag.Var(&fileSecrets, "secret-file", "Create CI secret from file (eq. --secret-file=secret_name=./path/to/file)")
+	flag.Var(&vaultSecrets, "secret-vault", "Create CI secret from HashiCorp Vault KV secret (eq. --secret-vault=secret_name=secret/path#field)")
+	flag.IntVar(&protection.RequiredReviews, "required-reviews", 0, "Protect branch by requiring a given number of approving reviews of pull requests")
+	flag.BoolVar(&protection.DismissStaleReviews, "dismiss-stale-reviews", false, "Protect branch by dismissing approving reviews when new commits are pushed")
+	flag.Var((*SliceFlag)(&protection.RequiredStatusChecks), "required-status-check", "Protect branch by requiring status check to pass before merging. Can be specified multiple times")
+	flag.BoolVar(&protection.StrictStatusChecks, "strict-status-checks", false, "Protect branch by requiring branches to be up to date before merging")
+	flag.BoolVar(&protection.EnforceAdmins, "enforce-admins", false, "Enforce branch protection rules for administrators")
+	flag.Var((*SliceFlag)(&protection.RestrictPushUsers), "restrict-push-user", "Protect branch by allowing only given users to push to it. Can be specified multiple times")
+	flag.Var((*SliceFlag)(&protection.RestrictPushTeams), "restrict-push-team", "Protect branch by allowing only given teams (groups on Bitbucket Server) to push to it. Can be specified multiple times")
+	flag.StringVar(&tokenFile, "token-file", "", "Read personal token from a file")
+	flag.StringVar(&tokenVault, "token-vault", "", "Read personal token from HashiCorp Vault KV secret (eq. --token-vault=secret/path#field), Vault is configured with VAULT_ADDR and VAULT_TOKEN environment variables")
+	flag.Parse()
+
+	ui := console.New(os.Stdin, os.Stdout)
+
+	if _, ok := tokenEnv[provider]; !ok {
+		flag.Usage()
+		ui.Fatalf("Unknown provider %#v, must be one of: github, gitea, bitbucket\n", provider)
+	}
+
+	owner, name := flag.Arg(0), flag.Arg(1)
+	if owner == "" {
+		flag.Usage()
+		ui.Fatalf("Repository owner is empty\n")
+	}
+
+	if name == "" {
+		flag.Usage()
+		ui.Fatalf("Repository name is empty\n")
+	}
+
+	if host == "" && provider == "github" {
+		host = auth.GitHubHost
+	}
+
+	if host == "" {
+		flag.Usage()
+		ui.Fatalf("Host of %v is empty\n", provider)
+	}
+
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+
+	u, err := url.Parse(host)
+	if err != nil || u.Host == "" {
+		ui.Fatalf("Invalid host %#v\n", host)
+	}
+
+	u = &url.URL{Scheme: u.Scheme, Host: u.Host, Path: strings.TrimSuffix(u.Path, "/")}
+
+	// secrets are read before repository is created
+	secrets, err := keychainx.ReadSecrets(literalSecrets, fileSecrets, vaultSecrets)
+	if err != nil {
+		ui.Fatalf("An error occurred while reading secrets: %v\n", err)
+	}
+
+	var key []byte
+	keyPath, keyPerm := SplitPermissions(deployKey, "ro")
+	if deployKey != "" {
+		if key, err = ioutil.ReadFile(keyPath); err != nil {
+			ui.Fatalf("Unable to read deployment key: %v\n", err)
+		}
+	}
+
+	newProvider := func(token string) scm.Provider {
+		switch provider {
+		case "gitea":
+			return scm.NewGitea(u, token)
+		case "bitbucket":
+			return scm.NewBitbucket(u, token)
+		}
+
+		cli, err := auth.NewGitHubClient(u.String(), token)
+		if err != nil {
+			ui.Fatalf("An error occurred while creating GitHub client: %v\n", err)
+		}
+
+		return scm.NewGitHub(cli)
+	}
+
+	credentials := auth.TokenChain{
+		UI:      ui,
+		Service: provider,
+		Env:     tokenEnv[provider],
+		File:    tokenFile,
+		Vault:   tokenVault,
+		Verify: func(user, pass string) error {
+			_, err := newProvider(pass).User()
+			if err == scm.ErrUnauthorized {
+				return keychainx.ErrRejected
+			}
+
+			return err
+		},
+		// ask for token until valid one is entered
+		Ask: func() (string, string) {
+			for {
+				pass := ui.ReadString(fmt.Sprintf("Enter your personal token for %v: ", u.Host))
+
+				user, err := newProvider(pass).User()
+				if err == nil {
+					return user, pass
+				}
+
+				ui.Errorf("An error occurred while validating credentials: %v\n", err)
+				ui.Errorf("Credentials do not appear to be valid, try again...\n")
+			}
+		},
+	}
+
+	// credentials are stored per host
+	_, token, err := credentials.Credentials(u.Host)
+	if err != nil {
+		ui.Fatalf("An error occurred while loading credentials: %v\n", err)
+	}
+
+	p := newProvider(token)
+
+	// create repository
+	repo, err := p.CreateRepository(owner, name, !public)
+	if err == scm.ErrExists {
+		ui.Printf("Repository %v/%v already exists, skipping repository configuration...\n", owner, name)
+		return
+	}
+
+	if err != nil {
+		ui.Fatalf("An error occurred while creating repository: %v\n", err)
+	}
+
+	ui.Successf("New repository created at %v\n", repo.WebURL)
+
+	// init repository
+	if err := run("git", "init"); err != nil {
+		ui.Fatalf("An error occurred while running git init: %v\n", err)
+	}
+
+	// add all files
+	if err := run("git", "add", "-A"); err != nil {
+		ui.Fatalf("An error occurred while running git add: %v\n", err)
+	}
+
+	// remove starter files, but keep recorded answers so project can be upgraded later
+	if err := run("git", "rm", "-r", "--cached", "--ignore-unmatch", ".starter", ".starter.yml"); err != nil {
+		ui.Fatalf("An error occurred while running git rm: %v\n", err)
+	}
+
+	// commit
+	if err := run("git", "commit", "-m", "Initial commit"); err != nil {
+		ui.Fatalf("An error occurred while running git commit: %v\n", err)
+	}
+
+	if err := run("git", "remote", "add", remote, repo.CloneURL); err != nil {
+		ui.Fatalf("An error occurred while running git remote add: %v\n", err)
+	}
+
+	if err := run("git", "push", "--set-upstream", remote, branch); err != nil {
+		ui.Fatalf("An error occurred while running git push: %v\n", err)
+	}
+
+	if protection.Enabled() {
+		ui.Printf("Protecting branch %#v\n", branch)
+
+		if err := p.ProtectBranch(owner, repo.Slug, branch, protection); err != nil {
+			ui.Errorf("An error occurred while protecting branch %#v: %v\n", branch, err)
+		}
+	}
+
+	for _, c := range collaborators {
+		user, perm := SplitPermissions(c, string(scm.Write))
+
+		if err := p.AddCollaborator(owner, repo.Slug, user, scm.Permission(perm)); err != nil {
+			ui.Errorf("An error occurred while adding %#v collaborator: %v\n", c, err)
+		}
+	}
+
+	if deployKey != "" {
+		ui.Printf("Adding deployment key %#v with %#v permissions\n", keyPath, keyPerm)
+
+		if err := p.AddDeployKey(owner, repo.Slug, "Deploy Key", string(key), keyPerm != "rw"); err != nil {
+			ui.Errorf("An error occurred while adding %#v deployment key: %v\n", keyPath, err)
+		}
+	}
+
+	for _, s := range secrets {
+		ui.Printf("Adding secret %#v...\n", s.Name)
+
+		if err := p.SetSecret(owner, repo.Slug, s.Name, s.Value); err != nil {
+			ui.Errorf("An error occurred while adding secret: %v\n", err)
+		}
+	}
+}
+
+// Run a cli command
+func run(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Stderr = os.Stderr
+	cmd.Stdout = os.Stdout
+	cmd.Stdin = os.Stdin
+
+	return cmd.Run()
+}
+
+func SplitPermissions(c, d string) (string, string) {
+	if parts := strings.SplitN(c, ":", 2); len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+
+	return c, d
+}
+
+type SliceFlag []string
+
+func (s *SliceFlag) Set(v string) error {
+	values := strings.Split(v, ",")
+	for _, v := range values {
+		*s = append(*s, strings.TrimSpace(v))
+	}
+
+	return nil
+}
+
+func (s *SliceFlag) String() string {
+	return strings.Join(*s, ",")
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/adobe/go-starter/pkg/auth"
+	"github.com/adobe/go-starter/pkg/console"
+	"github.com/adobe/go-starter/pkg/keychainx"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// authenticate manages credentials stored in keychain by go-starter-github, go-starter-gitlab and go-starter-drone
+func authenticate(args []string) {
+	var kind string
+
+	fs := flag.NewFlagSet("auth", flag.ExitOnError)
+	fs.Usage = func() {
+		out := fs.Output()
+		_, _ = fmt.Fprintf(out, "go-starter version %v (commit %v)\n", version, commit)
+		_, _ = fmt.Fprintf(out, "\n")
+		_, _ = fmt.Fprintf(out, "Usage: %s auth list\n", os.Args[0])
+		_, _ = fmt.Fprintf(out, "       %s auth login [flags] <host>\n", os.Args[0])
+		_, _ = fmt.Fprintf(out, "       %s auth logout <host>\n", os.Args[0])
+		_, _ = fmt.Fprintf(out, "       %s auth verify [flags] <host>\n", os.Args[0])
+		_, _ = fmt.Fprintf(out, "\nManages credentials stored in keychain: lists hosts, asks for new credentials, removes or validates them.\n")
+		_, _ = fmt.Fprintf(out, "\nExample:\n")
+		_, _ = fmt.Fprintf(out, "    %s auth login github.com\n", os.Args[0])
+		_, _ = fmt.Fprintf(out, "    %s auth verify -type drone https://cloud.drone.io\n", os.Args[0])
+		_, _ = fmt.Fprintf(out, "\nFlags:\n")
+		fs.PrintDefaults()
+		_, _ = fmt.Fprintf(out, "\n")
+	}
+	fs.StringVar(&kind, "type", "", "Type of the host: github, gitlab or drone. By default it's detected from host name and stored credentials.")
+
+	if len(args) == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	command := args[0]
+	_ = fs.Parse(args[1:])
+
+	ui := console.New(os.Stdin, os.Stdout)
+
+	if command == "list" {
+		items, err := keychainx.List()
+		if err != nil {
+			ui.Fatalf("An error occurred while reading keychain: %v\n", err)
+		}
+
+		if len(items) == 0 {
+			ui.Printf("There are no stored credentials\n")
+		}
+
+		for _, item := range items {
+			ui.Printf("%v\t%v\n", item.Label, item.User)
+		}
+
+		return
+	}
+
+	if fs.Arg(0) == "" {
+		fs.Usage()
+		ui.Fatalf("Host is empty\n")
+	}
+
+	// host can be passed as URL, eq. Drone URL with http scheme
+	u, err := url.Parse(fs.Arg(0))
+	if err != nil || u.Host == "" {
+		u, err = url.Parse("https://" + fs.Arg(0))
+	}
+
+	if err != nil || u.Host == "" {
+		ui.Fatalf("Invalid host %#v\n", fs.Arg(0))
+	}
+
+	label := u.Host
+
+	switch command {
+	case "login":
+		kind = hostType(ui, label, "", kind)
+
+		switch kind {
+		case "github":
+			user, pass := auth.AskGitHub(ui, fs.Arg(0))
+			err = keychainx.Save(label, user, pass)
+		case "gitlab":
+			user, pass := auth.AskGitLab(ui, &url.URL{Scheme: u.Scheme, Host: u.Host})
+			err = keychainx.Save(label, user, pass)
+		default:
+			err = keychainx.Save(label, "drone", auth.AskDrone(ui, u))
+		}
+
+		if err != nil {
+			ui.Fatalf("An error occurred while saving keychain: %v\n", err)
+		}
+
+		ui.Successf("Credentials for %v are saved\n", label)
+
+	case "logout":
+		err := keychainx.Remove(label)
+		if err == keychainx.ErrNotFound {
+			ui.Fatalf("There are no stored credentials for %v\n", label)
+		}
+
+		if err != nil {
+			ui.Fatalf("An error occurred while removing credentials from keychain: %v\n", err)
+		}
+
+		ui.Successf("Credentials for %v are removed\n", label)
+
+	case "verify":
+		user, pass, err := keychainx.Load(label)
+		if err == keychainx.ErrNotFound {
+			ui.Fatalf("There are no stored credentials for %v, run \"%v auth login %v\"\n", label, os.Args[0], fs.Arg(0))
+		}
+
+		if err != nil {
+			ui.Fatalf("An error occurred while reading keychain: %v\n", err)
+		}
+
+		switch hostType(ui, label, user, kind) {
+		case "github":
+			_, err = auth.VerifyGitHub(fs.Arg(0), pass)
+		case "gitlab":
+			_, err = auth.VerifyGitLab(&url.URL{Scheme: u.Scheme, Host: u.Host}, pass)
+		default:
+			err = auth.VerifyDrone(u, pass)
+		}
+
+		if err == keychainx.ErrRejected {
+			ui.Fatalf("Credentials for %v are rejected, run \"%v auth login %v\" to replace them\n", label, os.Args[0], fs.Arg(0))
+		}
+
+		if err != nil {
+			ui.Fatalf("An error occurred while validating credentials: %v\n", err)
+		}
+
+		ui.Successf("Credentials for %v are valid\n", label)
+
+	default:
+		fs.Usage()
+		ui.Fatalf("Unknown command %#v, must be one of: list, login, logout, verify\n", command)
+	}
+}
+
+// hostType returns type passed with -type flag or detects it from host name and stored user
+func hostType(ui *console.Console, host, user, kind string) string {
+	switch {
+	case kind == "github" || kind == "gitlab" || kind == "drone":
+		return kind
+	case kind != "":
+		ui.Fatalf("Unknown type %#v, must be one of: github, gitlab, drone\n", kind)
+	case user == "drone":
+		return "drone"
+	case host == auth.GitHubHost || strings.HasPrefix(host, "github."):
+		return "github"
+	case host == auth.GitLabHost || strings.HasPrefix(host, "gitlab."):
+		return "gitlab"
+	case strings.Contains(host, "drone"):
+		return "drone"
+	}
+
+	ui.Fatalf("Unable to detect type of %v, use -type flag\n", host)
+	return ""
+}
@@ -29,27 +29,64 @@ func usage() {
 	_, _ = fmt.Fprintf(out, "go-starter version %v (commit %v)\n", version, commit)
 	_, _ = fmt.Fprintf(out, "\n")
 	_, _ = fmt.Fprintf(out, "Usage: %s [flags] <template> <destination>\n", os.Args[0])
+	_, _ = fmt.Fprintf(out, "       %s [flags] -replay <answers-file> <destination>\n", os.Args[0])
+	_, _ = fmt.Fprintf(out, "       %s upgrade [flags] [<project>]\n", os.Args[0])
+	_, _ = fmt.Fprintf(out, "       %s auth list|login|logout|verify [flags] [<host>]\n", os.Args[0])
 	_, _ = fmt.Fprintf(out, "\nExample:\n")
 	_, _ = fmt.Fprintf(out, "    %s -var \"app_name=awesome-project\" go-starter/awesome-starter awesome-project\n", os.Args[0])
+	_, _ = fmt.Fprintf(out, "    %s -replay awesome-project/%v awesome-project-copy\n", os.Args[0], maker.RecordFile)
 	_, _ = fmt.Fprintf(out, "\nFlags:\n")
 	flag.PrintDefaults()
 	_, _ = fmt.Fprintf(out, "\n")
 }
 
 func main() {
-	var skipClone bool
-	var template, destination, branch string
+	if len(os.Args) > 1 && os.Args[1] == "upgrade" {
+		upgrade(os.Args[2:])
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "auth" {
+		authenticate(os.Args[2:])
+		return
+	}
+
+	var skipClone, nonInteractive bool
+	var template, destination, branch, revision, answers, replay string
 	var vars = make(maker.Vars)
 
 	flag.Usage = usage
 	flag.Var(&vars, "var", "An additional variable. Can be used multiple times. Example: -var \"variable_name=value\"")
 	flag.BoolVar(&skipClone, "skip-clone", false, "Skip clone step, just enter destination directory and run tasks.")
 	flag.StringVar(&branch, "branch", "master", "Branch to checkout in template repository.")
+	flag.StringVar(&answers, "answers", "", "Read answers from YAML or JSON file, implies -non-interactive. Values passed with -var take precedence.")
+	flag.BoolVar(&nonInteractive, "non-interactive", false, "Do not prompt for answers, fail if any answer is missing or invalid.")
+	flag.StringVar(&replay, "replay", "", "Re-run template using answers file recorded in previously generated project (eq. "+maker.RecordFile+"), implies -non-interactive.")
 	flag.Parse()
 
 	ui := console.New(os.Stdin, os.Stdout)
 
 	template, destination = flag.Arg(0), flag.Arg(1)
+
+	// Use template, revision and answers from recorded answers file
+	if replay != "" {
+		record, err := maker.LoadRecord(replay)
+		if err != nil {
+			ui.Fatalf("An error occurred when reading recorded answers: %v\n", err)
+		}
+
+		template, destination = record.TemplateURL, flag.Arg(0)
+		branch, revision = record.TemplateBranch, record.TemplateRevision
+
+		for k, v := range record.Answers {
+			if _, ok := vars[k]; !ok {
+				vars[k] = v
+			}
+		}
+
+		nonInteractive = true
+	}
+
 	if template == "" {
 		flag.Usage()
 		ui.Fatalf("ERROR: template should not be empty, use Git repository URL\n")
@@ -60,6 +97,22 @@ func main() {
 		ui.Fatalf("ERROR: destination should not be empty, enter folder where you want to deploy new application\n")
 	}
 
+	// Load answers file before leaving current work dir
+	if answers != "" {
+		loaded, err := maker.LoadAnswers(answers)
+		if err != nil {
+			ui.Fatalf("An error occurred when reading answers file: %v\n", err)
+		}
+
+		for k, v := range loaded {
+			if _, ok := vars[k]; !ok {
+				vars[k] = v
+			}
+		}
+
+		nonInteractive = true
+	}
+
 	// Get clone URL
 	cloneURL := maker.ResolveTemplateURL(template)
 
@@ -72,11 +125,16 @@ func main() {
 	if !skipClone {
 		ui.Titlef("Cloning template %v\n", template)
 
-		if err := maker.Checkout(destination, cloneURL, branch); err != nil {
+		checkedOut, err := maker.Checkout(destination, cloneURL, branch, revision)
+		if err != nil {
 			ui.Fatalf("An error occurred: %v\n", err)
 		}
+
+		revision = checkedOut
 	}
 
+	vars["template_revision"] = revision
+
 	// Enter destination folder so all next steps are executed in current work dir
 	if err := os.Chdir(destination); err != nil {
 		ui.Fatalf("An error occurred when chdir to destination directory: %v\n", err)
@@ -88,28 +146,60 @@ func main() {
 		ui.Fatalf("An error occurred when reading .starter.yml from repository: %v\n", err)
 	}
 
+	// Password answers are not recorded, ask for them again when replaying
+	if replay != "" {
+		if vars, err = maker.Ask(ui, maker.Passwords(config.Questions), vars); err != nil {
+			ui.Fatalf("An error occurred when reading user input: %v\n", err)
+		}
+	}
+
 	// Ask questions
-	vars, err = maker.Ask(ui, config.Questions, vars)
+	if nonInteractive {
+		vars, err = maker.Answer(config.Questions, vars)
+	} else {
+		vars, err = maker.Ask(ui, config.Questions, vars)
+	}
+
 	if err != nil {
-		ui.Fatalf("An error occurred when reading user input", err)
+		ui.Fatalf("An error occurred when reading user input: %v\n", err)
+	}
+
+	// Record answers, so project can be re-generated or upgraded later
+	if err := maker.SaveRecord(maker.RecordFile, maker.NewRecord(config.Questions, vars)); err != nil {
+		ui.Fatalf("An error occurred when recording answers: %v\n", err)
 	}
 
 	// Run tasks
-	for _, task := range config.Tasks {
+	tasks(ui, config.Tasks, vars)
+
+	ui.Successf("You're all set, happy coding!\n")
+}
+
+// tasks from .starter.yml executed in current work dir
+func tasks(ui *console.Console, tasks []maker.Task, vars map[string]string) {
+	for _, task := range tasks {
 		if len(task.Command) == 0 {
 			ui.Fatalf("Task command can not be empty, check your .starter.yml\n")
 		}
 
 		name, args := task.Command[0], subst(task.Command[1:], vars)
 
+		ok, err := maker.When(task.When, vars)
+		if err != nil {
+			ui.Fatalf("An error occurred when evaluating condition of task %v: %v\n", name, err)
+		}
+
+		if !ok {
+			ui.Printf("Skipping task %v, condition %#v is not met\n", name, task.When)
+			continue
+		}
+
 		ui.Titlef("Running task %v...\n", name)
 
 		if err := maker.Run(vars, name, args...); err != nil {
 			ui.Fatalf("An error occurred when executing task: %v\n", err)
 		}
 	}
-
-	ui.Successf("You're all set, happy coding!\n")
 }
 
 // load .starter.yml
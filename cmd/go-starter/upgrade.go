@@ -0,0 +1,199 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/adobe/go-starter/pkg/console"
+	"github.com/adobe/go-starter/pkg/maker"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// upgrade project generated by go-starter to a newer revision of the template
+func upgrade(args []string) {
+	var nonInteractive bool
+	var branch, revision string
+	var vars = make(maker.Vars)
+
+	fs := flag.NewFlagSet("upgrade", flag.ExitOnError)
+	fs.Usage = func() {
+		out := fs.Output()
+		_, _ = fmt.Fprintf(out, "go-starter version %v (commit %v)\n", version, commit)
+		_, _ = fmt.Fprintf(out, "\n")
+		_, _ = fmt.Fprintf(out, "Usage: %s upgrade [flags] [<project>]\n", os.Args[0])
+		_, _ = fmt.Fprintf(out, "\nRe-renders the template at recorded and new revisions using answers from %v and merges the difference into the project.\n", maker.RecordFile)
+		_, _ = fmt.Fprintf(out, "\nExample:\n")
+		_, _ = fmt.Fprintf(out, "    %s upgrade awesome-project\n", os.Args[0])
+		_, _ = fmt.Fprintf(out, "\nFlags:\n")
+		fs.PrintDefaults()
+		_, _ = fmt.Fprintf(out, "\n")
+	}
+	fs.Var(&vars, "var", "An additional variable, for example an answer to a question added to the template. Can be used multiple times. Example: -var \"variable_name=value\"")
+	fs.StringVar(&branch, "branch", "", "Branch of template repository to upgrade to, recorded branch is used by default.")
+	fs.StringVar(&revision, "revision", "", "Revision (commit SHA) of template repository to upgrade to, the latest commit of the branch is used by default.")
+	fs.BoolVar(&nonInteractive, "non-interactive", false, "Do not prompt for answers to new questions, fail if any answer is missing or invalid.")
+	_ = fs.Parse(args)
+
+	ui := console.New(os.Stdin, os.Stdout)
+
+	project := fs.Arg(0)
+	if project == "" {
+		project = "."
+	}
+
+	project, err := filepath.Abs(project)
+	if err != nil {
+		ui.Fatalf("An error occurred when resolving project path: %v\n", err)
+	}
+
+	record, err := maker.LoadRecord(filepath.Join(project, maker.RecordFile))
+	if err != nil {
+		ui.Fatalf("An error occurred when reading recorded answers, make sure project was generated by go-starter: %v\n", err)
+	}
+
+	if branch == "" {
+		branch = record.TemplateBranch
+	}
+
+	// recorded answers, values passed with -var take precedence
+	for k, v := range record.Answers {
+		if _, ok := vars[k]; !ok {
+			vars[k] = v
+		}
+	}
+
+	vars["destination"] = filepath.Base(project)
+
+	tmp, err := ioutil.TempDir("", "go-starter-upgrade")
+	if err != nil {
+		ui.Fatalf("An error occurred when creating temporary directory: %v\n", err)
+	}
+
+	defer os.RemoveAll(tmp)
+
+	base, next := filepath.Join(tmp, "base"), filepath.Join(tmp, "next")
+
+	// password answers are shared by both renders, so they are asked only once
+	secrets := make(map[string]string)
+
+	ui.Titlef("Rendering template %v at recorded revision %v\n", record.TemplateURL, record.TemplateRevision)
+	_, baseQuestions := render(ui, base, record.TemplateURL, record.TemplateBranch, record.TemplateRevision, clone(vars), secrets, true)
+
+	ui.Titlef("Rendering template %v at new revision\n", record.TemplateURL)
+	vars, nextQuestions := render(ui, next, record.TemplateURL, branch, revision, clone(vars), secrets, nonInteractive)
+
+	ui.Titlef("Merging template changes into %v\n", project)
+
+	changes, err := maker.Merge(base, next, project)
+	for _, change := range changes {
+		switch change.Status {
+		case maker.Conflict, maker.Untouched:
+			ui.Errorf("%-10v %v: %v\n", change.Status, change.Path, change.Message)
+		default:
+			ui.Printf("%-10v %v\n", change.Status, change.Path)
+		}
+	}
+
+	if err != nil {
+		ui.Fatalf("An error occurred when merging template changes: %v\n", err)
+	}
+
+	if err := maker.SaveRecord(filepath.Join(project, maker.RecordFile), maker.NewRecord(append(baseQuestions, nextQuestions...), vars)); err != nil {
+		ui.Fatalf("An error occurred when recording answers: %v\n", err)
+	}
+
+	for _, change := range changes {
+		if change.Status == maker.Conflict {
+			ui.Fatalf("Project upgraded to revision %v with conflicts, resolve them before committing\n", vars["template_revision"])
+		}
+	}
+
+	ui.Successf("Project upgraded to revision %v, review changes before committing\n", vars["template_revision"])
+}
+
+// render template into a directory, running the same tasks as when project was generated
+func render(ui *console.Console, dir, template, branch, revision string, vars, secrets map[string]string, nonInteractive bool) (map[string]string, []maker.Question) {
+	checkedOut, err := maker.Checkout(dir, template, branch, revision)
+	if err != nil {
+		ui.Fatalf("An error occurred: %v\n", err)
+	}
+
+	vars["template_url"] = template
+	vars["template_branch"] = branch
+	vars["template_revision"] = checkedOut
+	vars["upgrade"] = "1"
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		ui.Fatalf("An error occurred when reading current work dir: %v\n", err)
+	}
+
+	if err := os.Chdir(dir); err != nil {
+		ui.Fatalf("An error occurred when chdir to template directory: %v\n", err)
+	}
+
+	defer func() {
+		_ = os.Chdir(cwd)
+	}()
+
+	config, err := load(".starter.yml")
+	if err != nil {
+		ui.Fatalf("An error occurred when reading .starter.yml from repository: %v\n", err)
+	}
+
+	// password answers are not recorded, ask for them unless entered for previous render
+	passwords := maker.Passwords(config.Questions)
+	for _, q := range passwords {
+		if v, ok := secrets[q.Name]; ok {
+			if _, ok := vars[q.Name]; !ok {
+				vars[q.Name] = v
+			}
+		}
+	}
+
+	if vars, err = maker.Ask(ui, passwords, vars); err != nil {
+		ui.Fatalf("An error occurred when reading user input: %v\n", err)
+	}
+
+	for _, q := range passwords {
+		if v, ok := vars[q.Name]; ok {
+			secrets[q.Name] = v
+		}
+	}
+
+	if nonInteractive {
+		vars, err = maker.Answer(config.Questions, vars)
+	} else {
+		vars, err = maker.Ask(ui, config.Questions, vars)
+	}
+
+	if err != nil {
+		ui.Fatalf("An error occurred when reading user input: %v\n", err)
+	}
+
+	tasks(ui, config.Tasks, vars)
+
+	return vars, config.Questions
+}
+
+// clone variables, so each render gets its own copy
+func clone(vars map[string]string) map[string]string {
+	out := make(map[string]string, len(vars))
+	for k, v := range vars {
+		out[k] = v
+	}
+
+	return out
+}
@@ -4,11 +4,13 @@ go 1.12
 
 require (
 	github.com/drone/drone-go v1.1.0
+	github.com/godbus/dbus/v5 v5.0.3
 	github.com/google/go-github v17.0.0+incompatible
 	github.com/google/go-querystring v1.0.0 // indirect
 	github.com/hashicorp/vault/api v1.0.2
 	github.com/keybase/go-keychain v0.0.0-20191220220820-f65a47cbe0b1
 	github.com/logrusorgru/aurora v0.0.0-20190428105938-cea283e61946
+	golang.org/x/crypto v0.0.0-20200622213623-75b288015ac9
 	golang.org/x/oauth2 v0.0.0-20190604053449-0f29369cfe45
 	gopkg.in/yaml.v2 v2.2.2
 )
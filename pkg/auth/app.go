@@ -0,0 +1,130 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"github.com/google/go-github/github"
+	"net/http"
+	"time"
+)
+
+// GitHubApp authenticates as GitHub App installation
+type GitHubApp struct {
+	Host string
+	ID   int64
+	// Key is a PEM encoded private key of the app
+	Key []byte
+	// Installation ID, when it's zero installation is looked up by Owner (organization or user)
+	Installation int64
+	Owner        string
+}
+
+// Token creates installation access token
+func (a GitHubApp) Token() (string, error) {
+	jwt, err := appJWT(a.ID, a.Key, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	cli, err := NewGitHubClient(a.Host, jwt)
+	if err != nil {
+		return "", err
+	}
+
+	ctx := context.Background()
+
+	id := a.Installation
+	if id == 0 {
+		installation, _, err := cli.Apps.FindOrganizationInstallation(ctx, a.Owner)
+		if e, ok := err.(*github.ErrorResponse); ok && e.Response != nil && e.Response.StatusCode == http.StatusNotFound {
+			installation, _, err = cli.Apps.FindUserInstallation(ctx, a.Owner)
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("unable to find GitHub App installation for %v: %v", a.Owner, err)
+		}
+
+		id = installation.GetID()
+	}
+
+	// go-github uses legacy endpoint which is not available anymore
+	req, err := cli.NewRequest("POST", fmt.Sprintf("app/installations/%v/access_tokens", id), nil)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github.machine-man-preview+json")
+
+	token := new(github.InstallationToken)
+	if _, err := cli.Do(ctx, req, token); err != nil {
+		return "", fmt.Errorf("unable to create GitHub App installation token: %v", err)
+	}
+
+	return token.GetToken(), nil
+}
+
+// appJWT creates JSON Web Token signed with app's private key (RS256), valid for 9 minutes
+func appJWT(id int64, key []byte, now time.Time) (string, error) {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return "", fmt.Errorf("GitHub App private key is not PEM encoded")
+	}
+
+	private, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, err8 := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err8 != nil {
+			return "", fmt.Errorf("unable to parse GitHub App private key: %v", err)
+		}
+
+		var ok bool
+		if private, ok = parsed.(*rsa.PrivateKey); !ok {
+			return "", fmt.Errorf("GitHub App private key is not RSA key")
+		}
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	// issued a minute ago to allow for clock drift
+	claims, err := json.Marshal(map[string]int64{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": id,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	hash := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, private, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", err
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGitHubApp(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify JWT signed by the app and return its claims
+	verify := func(r *http.Request) map[string]interface{} {
+		parts := strings.Split(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), ".")
+		if len(parts) != 3 {
+			return nil
+		}
+
+		signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		if rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, hash[:], signature) != nil {
+			return nil
+		}
+
+		var claims map[string]interface{}
+		data, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		_ = json.Unmarshal(data, &claims)
+
+		return claims
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if claims := verify(r); claims == nil || claims["iss"] != float64(7) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v3/orgs/adobe/installation":
+			_, _ = w.Write([]byte(`{"id": 42}`))
+		case "GET /api/v3/users/octocat/installation":
+			_, _ = w.Write([]byte(`{"id": 43}`))
+		case "POST /api/v3/app/installations/42/access_tokens":
+			_, _ = w.Write([]byte(`{"token": "org-token"}`))
+		case "POST /api/v3/app/installations/43/access_tokens":
+			_, _ = w.Write([]byte(`{"token": "user-token"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+		}
+	}))
+
+	defer server.Close()
+
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	tests := []struct {
+		app   GitHubApp
+		token string
+		fails bool
+	}{
+		{app: GitHubApp{ID: 7, Key: pkcs1, Owner: "adobe"}, token: "org-token"},
+		{app: GitHubApp{ID: 7, Key: pkcs1, Owner: "octocat"}, token: "user-token"},
+		{app: GitHubApp{ID: 7, Key: pkcs1, Installation: 43}, token: "user-token"},
+		{app: GitHubApp{ID: 7, Key: pkcs1, Owner: "unknown"}, fails: true},
+		{app: GitHubApp{ID: 8, Key: pkcs1, Owner: "adobe"}, fails: true},
+		{app: GitHubApp{ID: 7, Key: []byte("invalid"), Owner: "adobe"}, fails: true},
+	}
+
+	for _, test := range tests {
+		test.app.Host = server.URL
+
+		token, err := test.app.Token()
+		if (err != nil) != test.fails {
+			t.Errorf("Error of %#v does not match: got %v, want failure %v", test.app.Owner, err, test.fails)
+		}
+
+		if token != test.token {
+			t.Errorf("Token of %#v does not match: got %#v, want %#v", test.app.Owner, token, test.token)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package auth
+
+import (
+	"github.com/adobe/go-starter/pkg/console"
+	"github.com/adobe/go-starter/pkg/keychainx"
+)
+
+// TokenChain resolves token of a service the same way in all commands: from environment variable, token file,
+// HashiCorp Vault secret, git credential helpers (when enabled), keychain or, finally, user prompt. Token stored in
+// keychain is verified first, so revoked one is asked again. Token entered by user is saved into keychain.
+type TokenChain struct {
+	UI *console.Console
+
+	// Service name used in messages, eq. GitHub
+	Service string
+
+	// Env is a name of environment variable with token
+	Env string
+
+	// File and Vault secret (eq. secret/path#field) with token, empty values are skipped
+	File  string
+	Vault string
+
+	// GitCredential enables credentials stored by git credential helpers
+	GitCredential bool
+
+	// Verify credentials stored in keychain, keychainx.ErrRejected makes user to enter new ones
+	Verify func(user, pass string) error
+
+	// Ask user for valid credentials
+	Ask func() (string, string)
+}
+
+// Credentials with a given label, which is a host of the service
+func (c TokenChain) Credentials(label string) (string, string, error) {
+	// ask user for token when there is none, and save it into keychain
+	prompt := keychainx.ProviderFunc(func(label string) (string, string, error) {
+		user, pass := c.Ask()
+		if err := keychainx.Save(label, user, pass); err != nil {
+			c.UI.Errorf("An error occurred while saving keychain: %v\n", err)
+		}
+
+		return user, pass, nil
+	})
+
+	// token stored in keychain could be revoked, ask for new one in that case
+	stored := keychainx.Verified{
+		Provider: keychainx.Keychain{},
+		Verify: func(label, user, pass string) error {
+			return c.Verify(user, pass)
+		},
+		Rejected: func(label string) {
+			c.UI.Errorf("Credentials stored in keychain for %v were rejected by %v\n", label, c.Service)
+		},
+	}
+
+	chain := keychainx.Chain{
+		keychainx.Env(c.Env),
+		keychainx.File(c.File),
+		keychainx.Vault{Path: c.Vault},
+	}
+
+	if c.GitCredential {
+		chain = append(chain, keychainx.GitCredential{})
+	}
+
+	return append(chain, stored, prompt).Credentials(label)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package auth
+
+import (
+	"bytes"
+	"github.com/adobe/go-starter/pkg/console"
+	"github.com/adobe/go-starter/pkg/keychainx"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestTokenChain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"GO_STARTER_KEYCHAIN", "XDG_CONFIG_HOME", "AUTH_TEST_TOKEN"} {
+		defer os.Setenv(name, os.Getenv(name))
+	}
+
+	os.Setenv("GO_STARTER_KEYCHAIN", "file")
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	os.Setenv("AUTH_TEST_TOKEN", "")
+
+	if err := keychainx.Save("example.com", "octocat", "revoked"); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	out := &bytes.Buffer{}
+	asked := 0
+
+	chain := TokenChain{
+		UI:      console.New(strings.NewReader(""), out),
+		Service: "Example",
+		Env:     "AUTH_TEST_TOKEN",
+		Verify: func(user, pass string) error {
+			if pass != "valid" {
+				return keychainx.ErrRejected
+			}
+
+			return nil
+		},
+		Ask: func() (string, string) {
+			asked++
+			return "octocat", "valid"
+		},
+	}
+
+	// revoked token in keychain is replaced with the one entered by user
+	if _, pass, err := chain.Credentials("example.com"); err != nil || pass != "valid" || asked != 1 {
+		t.Errorf("Credentials do not match: got %#v (%v, asked %v times), want %#v", pass, err, asked, "valid")
+	}
+
+	if !strings.Contains(out.String(), "rejected by Example") {
+		t.Errorf("Output does not match: got %#v", out.String())
+	}
+
+	if _, pass, err := keychainx.Load("example.com"); err != nil || pass != "valid" {
+		t.Errorf("Stored credentials do not match: got %#v (%v), want %#v", pass, err, "valid")
+	}
+
+	// environment variable takes precedence
+	os.Setenv("AUTH_TEST_TOKEN", "from-env")
+
+	if _, pass, err := chain.Credentials("example.com"); err != nil || pass != "from-env" || asked != 1 {
+		t.Errorf("Credentials do not match: got %#v (%v, asked %v times), want %#v", pass, err, asked, "from-env")
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package auth
+
+import (
+	"context"
+	"github.com/adobe/go-starter/pkg/console"
+	"github.com/adobe/go-starter/pkg/keychainx"
+	"github.com/drone/drone-go/drone"
+	"golang.org/x/oauth2"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// NewDroneClient builds Drone client authenticated with personal token
+func NewDroneClient(u *url.URL, token string) drone.Client {
+	auth := new(oauth2.Config).Client(context.Background(), &oauth2.Token{AccessToken: token})
+	auth.Timeout = 30 * time.Second
+
+	return drone.NewClient(u.String(), auth)
+}
+
+// VerifyDrone personal token, keychainx.ErrRejected is returned when Drone does not accept it
+func VerifyDrone(u *url.URL, token string) error {
+	_, err := NewDroneClient(u, token).Self()
+	if err != nil && strings.Contains(err.Error(), "client error 401") {
+		return keychainx.ErrRejected
+	}
+
+	return err
+}
+
+// AskDrone asks user for Drone personal token until valid one is entered
+func AskDrone(ui *console.Console, u *url.URL) (pass string) {
+	for {
+		ui.Printf("Follow this link to get your Personal Token: %v://%v/account.\n", u.Scheme, u.Host)
+
+		pass = ui.ReadString("Enter your personal token: ")
+
+		err := VerifyDrone(u, pass)
+		if err == nil {
+			return
+		}
+
+		ui.Errorf("An error occurred while validating your personal token: %v\n", err)
+		ui.Errorf("Credentials do not appear to be valid, try again...\n")
+	}
+}
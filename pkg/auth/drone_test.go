@@ -0,0 +1,55 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package auth
+
+import (
+	"github.com/adobe/go-starter/pkg/keychainx"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestVerifyDrone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/user" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer valid" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message": "Unauthorized"}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"login": "octocat"}`))
+	}))
+
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+
+	tests := []struct {
+		token string
+		err   error
+	}{
+		{token: "valid", err: nil},
+		{token: "invalid", err: keychainx.ErrRejected},
+	}
+
+	for _, test := range tests {
+		if err := VerifyDrone(u, test.token); err != test.err {
+			t.Errorf("Error of %#v does not match: got %v, want %v", test.token, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"github.com/adobe/go-starter/pkg/console"
+	"github.com/adobe/go-starter/pkg/keychainx"
+	"github.com/google/go-github/github"
+	"golang.org/x/oauth2"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GitHubHost is a host of public GitHub
+const GitHubHost = "github.com"
+
+// GitHubURL returns web URL of GitHub or GitHub Enterprise host, host can be specified with scheme (https by default)
+// and context path
+func GitHubURL(host string) (*url.URL, error) {
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub host: %v", err)
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid GitHub host %#v", host)
+	}
+
+	return &url.URL{Scheme: u.Scheme, Host: u.Host, Path: strings.TrimSuffix(u.Path, "/")}, nil
+}
+
+// NewGitHubClient builds GitHub client authenticated with a token (personal token, OAuth token or GitHub App token).
+// GitHub Enterprise v3 API is used for hosts other than github.com.
+func NewGitHubClient(host, token string) (*github.Client, error) {
+	u, err := GitHubURL(host)
+	if err != nil {
+		return nil, err
+	}
+
+	client := oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+	client.Timeout = 30 * time.Second
+
+	if u.Host == GitHubHost {
+		return github.NewClient(client), nil
+	}
+
+	return github.NewEnterpriseClient(u.String()+"/api/v3/", u.String()+"/api/uploads/", client)
+}
+
+// ScopesError is returned when token has no required OAuth scopes
+type ScopesError struct {
+	Missing []string
+}
+
+func (e *ScopesError) Error() string {
+	return fmt.Sprintf("token is missing required scopes: %v", strings.Join(e.Missing, ", "))
+}
+
+// parentScopes lists scopes which include other scopes
+var parentScopes = map[string][]string{
+	"public_repo":      {"repo"},
+	"repo:status":      {"repo"},
+	"repo_deployment":  {"repo"},
+	"read:org":         {"write:org", "admin:org"},
+	"write:org":        {"admin:org"},
+	"read:repo_hook":   {"write:repo_hook", "admin:repo_hook"},
+	"write:repo_hook":  {"admin:repo_hook"},
+	"read:public_key":  {"write:public_key", "admin:public_key"},
+	"write:public_key": {"admin:public_key"},
+}
+
+// VerifyGitHub token and returns login of its user. keychainx.ErrRejected is returned when GitHub does not accept the
+// token, *ScopesError when token has no required scopes. Scopes are checked using X-OAuth-Scopes header, so only
+// OAuth and classic personal tokens are checked.
+func VerifyGitHub(host, token string, scopes ...string) (string, error) {
+	cli, err := NewGitHubClient(host, token)
+	if err != nil {
+		return "", err
+	}
+
+	self, resp, err := cli.Users.Get(context.Background(), "")
+	if e, ok := err.(*github.ErrorResponse); ok && e.Response != nil && e.Response.StatusCode == http.StatusUnauthorized {
+		return "", keychainx.ErrRejected
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if header, ok := resp.Header["X-Oauth-Scopes"]; ok {
+		granted := map[string]bool{}
+		for _, scope := range strings.Split(strings.Join(header, ","), ",") {
+			granted[strings.TrimSpace(scope)] = true
+		}
+
+		var missing []string
+		for _, scope := range scopes {
+			ok := granted[scope]
+			for _, parent := range parentScopes[scope] {
+				ok = ok || granted[parent]
+			}
+
+			if !ok {
+				missing = append(missing, scope)
+			}
+		}
+
+		if len(missing) > 0 {
+			return self.GetLogin(), &ScopesError{Missing: missing}
+		}
+	}
+
+	return self.GetLogin(), nil
+}
+
+// AskGitHub asks user for GitHub personal token until valid one with required scopes is entered, returns
+// login of the user and the token
+func AskGitHub(ui *console.Console, host string, scopes ...string) (user string, pass string) {
+	link := "https://git.io/fjisU"
+	if u, err := GitHubURL(host); err == nil && u.Host != GitHubHost {
+		link = u.String() + "/settings/tokens/new?scopes=repo&description=go-starter"
+	}
+
+	for {
+		ui.Printf("Follow this link and generate personal token: %v. Scroll to the bottom of the page and click \"Generate token\".\n", link)
+
+		pass = ui.ReadString("Enter your personal token: ")
+
+		user, err := VerifyGitHub(host, pass, scopes...)
+		if err == nil {
+			return user, pass
+		}
+
+		ui.Errorf("An error occurred while validating credentials: %v\n", err)
+		ui.Errorf("Credentials do not appear to be valid, try again...\n")
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package auth
+
+import (
+	"github.com/adobe/go-starter/pkg/keychainx"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGitHubURL(t *testing.T) {
+	tests := []struct {
+		host  string
+		url   string
+		fails bool
+	}{
+		{host: "github.com", url: "https://github.com"},
+		{host: "github.adobe.com", url: "https://github.adobe.com"},
+		{host: "https://github.adobe.com/", url: "https://github.adobe.com"},
+		{host: "http://localhost:8080", url: "http://localhost:8080"},
+		{host: "example.com/github/", url: "https://example.com/github"},
+		{host: "", fails: true},
+	}
+
+	for _, test := range tests {
+		u, err := GitHubURL(test.host)
+		if (err != nil) != test.fails {
+			t.Errorf("Error of %#v does not match: got %v, want failure %v", test.host, err, test.fails)
+		}
+
+		if err == nil && u.String() != test.url {
+			t.Errorf("URL of %#v does not match: got %#v, want %#v", test.host, u.String(), test.url)
+		}
+	}
+
+	cli, err := NewGitHubClient("github.adobe.com", "token")
+	if err != nil {
+		t.Fatalf("NewGitHubClient() failed: %v", err)
+	}
+
+	if cli.BaseURL.String() != "https://github.adobe.com/api/v3/" {
+		t.Errorf("Base URL does not match: got %#v, want %#v", cli.BaseURL.String(), "https://github.adobe.com/api/v3/")
+	}
+
+	cli, err = NewGitHubClient("http://example.com/github", "token")
+	if err != nil {
+		t.Fatalf("NewGitHubClient() failed: %v", err)
+	}
+
+	if cli.BaseURL.String() != "http://example.com/github/api/v3/" {
+		t.Errorf("Base URL does not match: got %#v, want %#v", cli.BaseURL.String(), "http://example.com/github/api/v3/")
+	}
+}
+
+func TestVerifyGitHub(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/user" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		switch r.Header.Get("Authorization") {
+		case "Bearer repo":
+			w.Header().Set("X-OAuth-Scopes", "repo, read:org")
+		case "Bearer public":
+			w.Header().Set("X-OAuth-Scopes", "public_repo")
+		case "Bearer app":
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message": "Bad credentials"}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"login": "octocat"}`))
+	}))
+
+	defer server.Close()
+
+	tests := []struct {
+		token  string
+		scopes []string
+		login  string
+		err    error
+	}{
+		{token: "repo", scopes: []string{"repo"}, login: "octocat"},
+		{token: "repo", scopes: []string{"public_repo", "read:org"}, login: "octocat"},
+		{token: "public", scopes: []string{"public_repo"}, login: "octocat"},
+		{token: "public", scopes: []string{"repo", "read:org"}, login: "octocat", err: &ScopesError{Missing: []string{"repo", "read:org"}}},
+		{token: "app", scopes: []string{"repo"}, login: "octocat"},
+		{token: "invalid", err: keychainx.ErrRejected},
+	}
+
+	for _, test := range tests {
+		login, err := VerifyGitHub(server.URL, test.token, test.scopes...)
+		if !reflect.DeepEqual(err, test.err) {
+			t.Errorf("Error of %#v does not match: got %v, want %v", test.token, err, test.err)
+		}
+
+		if login != test.login {
+			t.Errorf("Login of %#v does not match: got %#v, want %#v", test.token, login, test.login)
+		}
+	}
+}
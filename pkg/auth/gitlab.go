@@ -0,0 +1,81 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/adobe/go-starter/pkg/console"
+	"github.com/adobe/go-starter/pkg/keychainx"
+	"golang.org/x/oauth2"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// GitLabHost is a host of public GitLab
+const GitLabHost = "gitlab.com"
+
+// NewGitLabClient builds HTTP client authenticated with GitLab personal token
+func NewGitLabClient(token string) *http.Client {
+	client := oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+	client.Timeout = 30 * time.Second
+
+	return client
+}
+
+// VerifyGitLab personal token and returns username of its user, keychainx.ErrRejected is returned when GitLab does
+// not accept the token
+func VerifyGitLab(u *url.URL, token string) (string, error) {
+	resp, err := NewGitLabClient(token).Get(u.String() + "/api/v4/user")
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return "", keychainx.ErrRejected
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected response from GitLab: %v", resp.Status)
+	}
+
+	var user struct {
+		Username string `json:"username"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return "", err
+	}
+
+	return user.Username, nil
+}
+
+// AskGitLab asks user for GitLab personal token until valid one is entered, returns username of the user and the token
+func AskGitLab(ui *console.Console, u *url.URL) (user string, pass string) {
+	for {
+		ui.Printf("Follow this link and create personal token with \"api\" scope: %v/-/profile/personal_access_tokens?name=go-starter&scopes=api.\n", u)
+
+		pass = ui.ReadString("Enter your personal token: ")
+
+		user, err := VerifyGitLab(u, pass)
+		if err == nil {
+			return user, pass
+		}
+
+		ui.Errorf("An error occurred while validating credentials: %v\n", err)
+		ui.Errorf("Credentials do not appear to be valid, try again...\n")
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package auth
+
+import (
+	"github.com/adobe/go-starter/pkg/keychainx"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestVerifyGitLab(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v4/user" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer valid" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message": "401 Unauthorized"}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"username": "octocat"}`))
+	}))
+
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+
+	tests := []struct {
+		token string
+		user  string
+		err   error
+	}{
+		{token: "valid", user: "octocat", err: nil},
+		{token: "invalid", user: "", err: keychainx.ErrRejected},
+	}
+
+	for _, test := range tests {
+		user, err := VerifyGitLab(u, test.token)
+		if err != test.err {
+			t.Errorf("Error of %#v does not match: got %v, want %v", test.token, err, test.err)
+		}
+
+		if user != test.user {
+			t.Errorf("User of %#v does not match: got %#v, want %#v", test.token, user, test.user)
+		}
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"bufio"
 	"fmt"
 	"github.com/logrusorgru/aurora"
+	"golang.org/x/crypto/ssh/terminal"
 	"io"
 	"os"
 	"strings"
@@ -36,6 +37,21 @@ func (l *Console) ReadString(prompt string) string {
 	return strings.TrimSuffix(input, "\n")
 }
 
+// ReadPassword reads a line without echoing it, when reader is not a terminal it behaves like ReadString
+func (l *Console) ReadPassword(prompt string) string {
+	f, ok := l.r.(*os.File)
+	if !ok || !terminal.IsTerminal(int(f.Fd())) {
+		return l.ReadString(prompt)
+	}
+
+	l.Printf(prompt)
+
+	input, _ := terminal.ReadPassword(int(f.Fd()))
+	l.Printf("\n")
+
+	return string(input)
+}
+
 func (l *Console) Debugf(format string, args ...interface{}) {
 	_, _ = fmt.Fprintf(l.w, aurora.Gray(0, format).String(), args...)
 }
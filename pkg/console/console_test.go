@@ -28,3 +28,15 @@ func TestConsole_ReadString(t *testing.T) {
 		t.Errorf("Input does not match: got %#v, want %#v", got, want)
 	}
 }
+
+func TestConsole_ReadPassword(t *testing.T) {
+	want := "secret"
+
+	c := New(strings.NewReader(want+"\n"), ioutil.Discard)
+
+	got := c.ReadPassword("")
+
+	if want != got {
+		t.Errorf("Input does not match: got %#v, want %#v", got, want)
+	}
+}
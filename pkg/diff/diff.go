@@ -0,0 +1,255 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+// Package diff produces line based differences in unified format
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	equal  = ' '
+	del    = '-'
+	insert = '+'
+)
+
+type edit struct {
+	kind byte
+	text string
+}
+
+// Unified diff between two texts, with given number of context lines around changes.
+// Returns empty string if texts are equal.
+func Unified(fromName, toName, from, to string, context int) string {
+	if from == to {
+		return ""
+	}
+
+	edits := myers(lines(from), lines(to))
+
+	out := &strings.Builder{}
+	_, _ = fmt.Fprintf(out, "--- %v\n+++ %v\n", fromName, toName)
+
+	// position of each edit in both texts
+	aPos, bPos := make([]int, len(edits)+1), make([]int, len(edits)+1)
+	for i, e := range edits {
+		aPos[i+1], bPos[i+1] = aPos[i], bPos[i]
+		if e.kind != insert {
+			aPos[i+1]++
+		}
+
+		if e.kind != del {
+			bPos[i+1]++
+		}
+	}
+
+	for i := 0; i < len(edits); {
+		if edits[i].kind == equal {
+			i++
+			continue
+		}
+
+		start, end := hi(0, i-context), i
+
+		// extend hunk while next change is close enough to share context
+		for {
+			for end < len(edits) && edits[end].kind != equal {
+				end++
+			}
+
+			next := end
+			for next < len(edits) && edits[next].kind == equal {
+				next++
+			}
+
+			if next < len(edits) && next-end <= 2*context {
+				end = next
+				continue
+			}
+
+			end = lo(len(edits), end+context)
+			break
+		}
+
+		aStart, aLen := aPos[start], aPos[end]-aPos[start]
+		bStart, bLen := bPos[start], bPos[end]-bPos[start]
+
+		if aLen > 0 {
+			aStart++
+		}
+
+		if bLen > 0 {
+			bStart++
+		}
+
+		_, _ = fmt.Fprintf(out, "@@ -%v +%v @@\n", span(aStart, aLen), span(bStart, bLen))
+
+		for _, e := range edits[start:end] {
+			out.WriteByte(e.kind)
+			out.WriteString(e.text)
+
+			if !strings.HasSuffix(e.text, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		i = end
+	}
+
+	return out.String()
+}
+
+// span of lines in hunk header
+func span(start, length int) string {
+	if length == 1 {
+		return fmt.Sprint(start)
+	}
+
+	return fmt.Sprintf("%v,%v", start, length)
+}
+
+// lines of the text, including line endings
+func lines(text string) []string {
+	var out []string
+
+	for text != "" {
+		n := strings.IndexByte(text, '\n')
+		if n < 0 {
+			n = len(text) - 1
+		}
+
+		out, text = append(out, text[:n+1]), text[n+1:]
+	}
+
+	return out
+}
+
+// maxDistance limits memory used by myers, texts which differ more are diffed as full replacement
+const maxDistance = 2000
+
+// myers computes the shortest edit script between a and b using Myers' algorithm
+func myers(a, b []string) []edit {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+
+	v := make([]int, 2*offset+1)
+
+	// trace keeps state of v before each step, only the window which can be used at that step
+	var trace [][]int
+
+	found := false
+
+	for d := 0; d <= n+m && d <= maxDistance && !found; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+
+	if !found {
+		return replacement(a, b)
+	}
+
+	// walk back through the trace to collect edits
+	var edits []edit
+
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		window := trace[d]
+		at := func(k int) int {
+			return window[k+d+1]
+		}
+
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			edits = append(edits, edit{kind: equal, text: a[x-1]})
+			x, y = x-1, y-1
+		}
+
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, edit{kind: insert, text: b[y-1]})
+			} else {
+				edits = append(edits, edit{kind: del, text: a[x-1]})
+			}
+		}
+
+		x, y = prevX, prevY
+	}
+
+	// edits were collected from the end
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+
+	return edits
+}
+
+// replacement of all lines of a with all lines of b
+func replacement(a, b []string) []edit {
+	edits := make([]edit, 0, len(a)+len(b))
+	for _, line := range a {
+		edits = append(edits, edit{kind: del, text: line})
+	}
+
+	for _, line := range b {
+		edits = append(edits, edit{kind: insert, text: line})
+	}
+
+	return edits
+}
+
+func lo(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func hi(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name, from, to, want string
+	}{
+		{
+			name: "equal",
+			from: "a\nb\n",
+			to:   "a\nb\n",
+			want: "",
+		},
+		{
+			name: "change",
+			from: "a\nb\nc\n",
+			to:   "a\nB\nc\n",
+			want: "--- a/file\n+++ b/file\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name: "separate hunks",
+			from: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			to:   "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n",
+			want: "--- a/file\n+++ b/file\n@@ -1,2 +1,2 @@\n-1\n+one\n 2\n@@ -9,2 +9,2 @@\n 9\n-10\n+ten\n",
+		},
+		{
+			name: "insert into empty",
+			from: "",
+			to:   "a\n",
+			want: "--- a/file\n+++ b/file\n@@ -0,0 +1 @@\n+a\n",
+		},
+		{
+			name: "no newline at end",
+			from: "a\nb",
+			to:   "a\nc",
+			want: "--- a/file\n+++ b/file\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := Unified("a/file", "b/file", test.from, test.to, 1)
+
+			if got != test.want {
+				t.Errorf("Diff does not match:\ngot:\n%v\nwant:\n%v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnified_LargeDistance(t *testing.T) {
+	var from, to strings.Builder
+	for i := 0; i < maxDistance; i++ {
+		from.WriteString("a\n")
+		to.WriteString("b\n")
+	}
+
+	got := Unified("a/file", "b/file", from.String(), to.String(), 3)
+
+	if want := "@@ -1,2000 +1,2000 @@\n"; !strings.Contains(got, want) {
+		t.Errorf("Diff should contain a single hunk %#v", want)
+	}
+}
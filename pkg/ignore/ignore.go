@@ -0,0 +1,202 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+// Package ignore matches paths against list of patterns in gitignore format
+package ignore
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+type pattern struct {
+	re     *regexp.Regexp
+	negate bool
+	dir    bool
+}
+
+// Matcher of paths, patterns defined later take precedence over patterns defined earlier
+type Matcher struct {
+	patterns []pattern
+}
+
+// New matcher from list of patterns, blank lines and comments are ignored
+func New(lines ...string) (*Matcher, error) {
+	m := &Matcher{}
+	if err := m.Add(lines...); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Add patterns to the matcher, invalid pattern is returned as an error and patterns after it are not added
+func (m *Matcher) Add(lines ...string) error {
+	for _, line := range lines {
+		p, ok, err := parse(line)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			m.patterns = append(m.patterns, p)
+		}
+	}
+
+	return nil
+}
+
+// Load patterns from a file into the matcher, missing file is not an error
+func (m *Matcher) Load(file string) error {
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		if err := m.Add(scanner.Text()); err != nil {
+			return fmt.Errorf("%v:%v: %v", file, n, err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Match tells if path (slash separated, relative to the root) is ignored. Path is ignored
+// if any of its parent directories is ignored as well.
+func (m *Matcher) Match(name string, dir bool) bool {
+	name = strings.Trim(path.Clean(name), "/")
+
+	parts := strings.Split(name, "/")
+	for i := 1; i < len(parts); i++ {
+		if m.match(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+
+	return m.match(name, dir)
+}
+
+// match path itself without checking its parents
+func (m *Matcher) match(name string, dir bool) bool {
+	ignored := false
+
+	for _, p := range m.patterns {
+		if p.dir && !dir {
+			continue
+		}
+
+		if p.re.MatchString(name) {
+			ignored = !p.negate
+		}
+	}
+
+	return ignored
+}
+
+// parse a line of gitignore file
+func parse(line string) (pattern, bool, error) {
+	var p pattern
+
+	original := line
+
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return p, false, nil
+	}
+
+	if strings.HasPrefix(line, "!") {
+		p.negate, line = true, line[1:]
+	} else if strings.HasPrefix(line, `\`) {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		p.dir, line = true, strings.TrimRight(line, "/")
+	}
+
+	if line == "" {
+		return p, false, nil
+	}
+
+	// pattern without slashes matches file name at any level, otherwise it's relative to the root
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	expr := "^"
+	if !anchored && !strings.HasPrefix(line, "**") {
+		expr += "(?:.*/)?"
+	}
+
+	re, err := regexp.Compile(expr + translate(line) + "$")
+	if err != nil {
+		return p, false, fmt.Errorf("invalid pattern %#v: %v", original, err)
+	}
+
+	p.re = re
+
+	return p, true, nil
+}
+
+// translate glob into regular expression
+func translate(glob string) string {
+	var expr strings.Builder
+
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; {
+		case strings.HasPrefix(glob[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**") && i+3 == len(glob):
+			expr.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				expr.WriteString(`\[`)
+				continue
+			}
+
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+
+			expr.WriteString("[" + strings.Replace(class, `\`, `\\`, -1) + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			expr.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return expr.String()
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package ignore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMatcher_Match(t *testing.T) {
+	m, err := New(
+		"# comment",
+		"",
+		"*.png",
+		"!logo.png",
+		"vendor/",
+		"/fixtures",
+		"docs/*.md",
+		"**/generated/**",
+		"build/**/*.out",
+		"file[0-9].txt",
+		`\#hash`,
+	)
+	if err != nil {
+		t.Fatalf("New should not return an error, but it returned %v", err)
+	}
+
+	tests := []struct {
+		path    string
+		dir     bool
+		ignored bool
+	}{
+		{path: "image.png", ignored: true},
+		{path: "assets/image.png", ignored: true},
+		{path: "assets/logo.png", ignored: false},
+		{path: "vendor", dir: true, ignored: true},
+		{path: "vendor", dir: false, ignored: false},
+		{path: "vendor/lib/file.go", ignored: true},
+		{path: "pkg/vendor/file.go", ignored: true},
+		{path: "fixtures", dir: true, ignored: true},
+		{path: "fixtures/data.json", ignored: true},
+		{path: "pkg/fixtures/data.json", ignored: false},
+		{path: "docs/readme.md", ignored: true},
+		{path: "docs/api/readme.md", ignored: false},
+		{path: "pkg/generated/file.go", ignored: true},
+		{path: "generated/deep/file.go", ignored: true},
+		{path: "build/a.out", ignored: true},
+		{path: "build/x/y/a.out", ignored: true},
+		{path: "file1.txt", ignored: true},
+		{path: "fileA.txt", ignored: false},
+		{path: "#hash", ignored: true},
+		{path: "main.go", ignored: false},
+		{path: "./vendor/lib/file.go", ignored: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			if got := m.Match(test.path, test.dir); got != test.ignored {
+				t.Errorf("Match result does not match: got %v, want %v", got, test.ignored)
+			}
+		})
+	}
+}
+
+func TestMatcher_Invalid(t *testing.T) {
+	if _, err := New("*.go", "[z-a].txt"); err == nil || !strings.Contains(err.Error(), "[z-a].txt") {
+		t.Errorf("New should return an error with invalid pattern, but it returned %v", err)
+	}
+
+	dir, err := ioutil.TempDir("", "ignore")
+	if err != nil {
+		t.Fatalf("Unable to create temporary dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, ".starterignore")
+	if err := ioutil.WriteFile(file, []byte("*.go\n[z-a].txt\n"), 0666); err != nil {
+		t.Fatalf("Unable to write ignore file: %v", err)
+	}
+
+	if err := (&Matcher{}).Load(file); err == nil || !strings.Contains(err.Error(), file+":2:") {
+		t.Errorf("Load should return an error with line number of invalid pattern, but it returned %v", err)
+	}
+}
@@ -14,3 +14,9 @@ package keychainx
 import "errors"
 
 var ErrNotFound = errors.New("item not found")
+
+// ErrRejected is returned by credentials verification when credentials are not valid
+var ErrRejected = errors.New("credentials are rejected")
+
+// errUnavailable is returned when OS keychain is not available
+var errUnavailable = errors.New("keychain is not available")
@@ -0,0 +1,307 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package keychainx
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/crypto/scrypt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// scryptCost is a CPU/memory cost parameter of key derivation
+var scryptCost = 1 << 15
+
+// fileStore stores credentials in a file encrypted with AES-GCM using a key derived from passphrase
+type fileStore struct {
+	path       string
+	keyFile    string
+	passphrase string
+}
+
+// sealed is an on-disk format of the credentials file
+type sealed struct {
+	Salt  []byte `json:"salt"`
+	Nonce []byte `json:"nonce"`
+	Data  []byte `json:"data"`
+}
+
+type credentials struct {
+	User     string `json:"user"`
+	Password string `json:"password"`
+}
+
+// openFile opens encrypted file store in user's config directory. Passphrase is taken from
+// GO_STARTER_KEYCHAIN_PASSPHRASE, otherwise from key file GO_STARTER_KEYCHAIN_KEY_FILE (default is
+// "credentials.key" next to the credentials file) which is generated on first save.
+func openFile() (store, error) {
+	dir, err := configDir()
+	if err != nil {
+		return nil, err
+	}
+
+	keyFile := os.Getenv("GO_STARTER_KEYCHAIN_KEY_FILE")
+	if keyFile == "" {
+		keyFile = filepath.Join(dir, "credentials.key")
+	}
+
+	return &fileStore{
+		path:       filepath.Join(dir, "credentials"),
+		keyFile:    keyFile,
+		passphrase: os.Getenv("GO_STARTER_KEYCHAIN_PASSPHRASE"),
+	}, nil
+}
+
+// configDir returns go-starter directory in $XDG_CONFIG_HOME or ~/.config
+func configDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "go-starter"), nil
+	}
+
+	home := os.Getenv("HOME")
+	if home == "" {
+		return "", fmt.Errorf("unable to locate config directory: neither $XDG_CONFIG_HOME nor $HOME are set")
+	}
+
+	return filepath.Join(home, ".config", "go-starter"), nil
+}
+
+// Save credentials with a given label, replacing existing ones
+func (s *fileStore) Save(label, user, password string) error {
+	items, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	items[label] = credentials{User: user, Password: password}
+
+	return s.write(items)
+}
+
+// Load credentials with a given label
+func (s *fileStore) Load(label string) (string, string, error) {
+	items, err := s.read()
+	if err != nil {
+		return "", "", err
+	}
+
+	c, ok := items[label]
+	if !ok {
+		return "", "", ErrNotFound
+	}
+
+	return c.User, c.Password, nil
+}
+
+// Remove credentials with a given label
+func (s *fileStore) Remove(label string) error {
+	items, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := items[label]; !ok {
+		return ErrNotFound
+	}
+
+	delete(items, label)
+
+	return s.write(items)
+}
+
+// List stored credentials
+func (s *fileStore) List() ([]Item, error) {
+	items, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Item
+	for label, c := range items {
+		list = append(list, Item{Label: label, User: c.User})
+	}
+
+	return list, nil
+}
+
+func (s *fileStore) Close() error {
+	return nil
+}
+
+// read and decrypt all credentials, missing file is treated as empty
+func (s *fileStore) read() (map[string]credentials, error) {
+	items := map[string]credentials{}
+
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return items, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var file sealed
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("unable to parse %v: %v", s.path, err)
+	}
+
+	passphrase, err := s.secret(false)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := s.cipher(passphrase, file.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(file.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("unable to decrypt %v: invalid nonce", s.path)
+	}
+
+	plain, err := gcm.Open(nil, file.Nonce, file.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt %v: wrong passphrase or corrupted file", s.path)
+	}
+
+	if err := json.Unmarshal(plain, &items); err != nil {
+		return nil, fmt.Errorf("unable to parse %v: %v", s.path, err)
+	}
+
+	return items, nil
+}
+
+// write encrypted credentials, file is replaced atomically
+func (s *fileStore) write(items map[string]credentials) error {
+	passphrase, err := s.secret(true)
+	if err != nil {
+		return err
+	}
+
+	plain, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+
+	file := sealed{Salt: make([]byte, 32)}
+	if _, err := io.ReadFull(rand.Reader, file.Salt); err != nil {
+		return err
+	}
+
+	gcm, err := s.cipher(passphrase, file.Salt)
+	if err != nil {
+		return err
+	}
+
+	file.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, file.Nonce); err != nil {
+		return err
+	}
+
+	file.Data = gcm.Seal(nil, file.Nonce, plain, nil)
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+
+	return writeFile(s.path, data)
+}
+
+// secret returns passphrase or contents of the key file, key file is generated if it's missing and create is set
+func (s *fileStore) secret(create bool) (string, error) {
+	if s.passphrase != "" {
+		return s.passphrase, nil
+	}
+
+	data, err := ioutil.ReadFile(s.keyFile)
+	if os.IsNotExist(err) && create {
+		key := make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, key); err != nil {
+			return "", err
+		}
+
+		data = []byte(hex.EncodeToString(key) + "\n")
+		err = writeFile(s.keyFile, data)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("unable to read key file: %v", err)
+	}
+
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return "", fmt.Errorf("key file %v is empty", s.keyFile)
+	}
+
+	return key, nil
+}
+
+// cipher derives key from passphrase and salt
+func (s *fileStore) cipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptCost, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// writeFile atomically writes data to a file readable by the owner only
+func writeFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(dir, "."+filepath.Base(path))
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0600); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
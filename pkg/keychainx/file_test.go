@@ -0,0 +1,184 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package keychainx
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFileStore(t *testing.T) {
+	defer func(cost int) { scryptCost = cost }(scryptCost)
+
+	scryptCost = 1 << 10
+
+	dir, err := ioutil.TempDir("", "keychainx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	s := &fileStore{path: filepath.Join(dir, "config", "credentials"), keyFile: filepath.Join(dir, "config", "credentials.key")}
+
+	if _, _, err := s.Load("github.com"); err != ErrNotFound {
+		t.Errorf("Load() error does not match: got %v, want %v", err, ErrNotFound)
+	}
+
+	if err := s.Save("github.com", "user", "secret"); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	if err := s.Save("github.com", "user", "token"); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	if err := s.Save("drone.io", "drone", "other"); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	user, password, err := s.Load("github.com")
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if user != "user" || password != "token" {
+		t.Errorf("Credentials do not match: got %#v, want %#v", []string{user, password}, []string{"user", "token"})
+	}
+
+	for _, path := range []string{s.path, s.keyFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("Mode of %v does not match: got %v, want %v", path, info.Mode().Perm(), os.FileMode(0600))
+		}
+	}
+
+	data, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(data), "token") || strings.Contains(string(data), "drone.io") {
+		t.Errorf("Credentials are stored in plain text: %s", data)
+	}
+
+	items, err := s.List()
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Errorf("Number of items does not match: got %#v, want %v", items, 2)
+	}
+
+	if err := s.Remove("drone.io"); err != nil {
+		t.Fatalf("Remove() failed: %v", err)
+	}
+
+	if err := s.Remove("drone.io"); err != ErrNotFound {
+		t.Errorf("Remove() error does not match: got %v, want %v", err, ErrNotFound)
+	}
+
+	if items, _ := s.List(); !reflect.DeepEqual(items, []Item{{Label: "github.com", User: "user"}}) {
+		t.Errorf("Items do not match: got %#v, want %#v", items, []Item{{Label: "github.com", User: "user"}})
+	}
+
+	wrong := &fileStore{path: s.path, keyFile: s.keyFile, passphrase: "wrong"}
+	if _, _, err := wrong.Load("github.com"); err == nil {
+		t.Errorf("Load() with wrong passphrase must fail")
+	}
+}
+
+func TestFileStorePassphrase(t *testing.T) {
+	defer func(cost int) { scryptCost = cost }(scryptCost)
+
+	scryptCost = 1 << 10
+
+	dir, err := ioutil.TempDir("", "keychainx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	s := &fileStore{path: filepath.Join(dir, "credentials"), keyFile: filepath.Join(dir, "credentials.key"), passphrase: "passphrase"}
+
+	if err := s.Save("github.com", "user", "token"); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	if _, err := os.Stat(s.keyFile); !os.IsNotExist(err) {
+		t.Errorf("Key file must not be created when passphrase is set")
+	}
+
+	_, password, err := (&fileStore{path: s.path, passphrase: "passphrase"}).Load("github.com")
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if password != "token" {
+		t.Errorf("Password does not match: got %#v, want %#v", password, "token")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	defer restoreEnv("GO_STARTER_KEYCHAIN", "XDG_CONFIG_HOME")()
+
+	os.Setenv("XDG_CONFIG_HOME", "/tmp/config")
+	os.Setenv("GO_STARTER_KEYCHAIN", "file")
+
+	s, err := open()
+	if err != nil {
+		t.Fatalf("open() failed: %v", err)
+	}
+
+	if f, ok := s.(*fileStore); !ok || f.path != "/tmp/config/go-starter/credentials" {
+		t.Errorf("Store does not match: got %#v", s)
+	}
+
+	os.Setenv("GO_STARTER_KEYCHAIN", "unknown")
+
+	if _, err := open(); err == nil {
+		t.Errorf("open() with unknown keychain must fail")
+	}
+}
+
+// restoreEnv returns function which restores environment variables to their current values, unsetting
+// variables which are not set now
+func restoreEnv(names ...string) func() {
+	values := make(map[string]*string)
+	for _, name := range names {
+		if v, ok := os.LookupEnv(name); ok {
+			values[name] = &v
+		} else {
+			values[name] = nil
+		}
+	}
+
+	return func() {
+		for name, v := range values {
+			if v == nil {
+				os.Unsetenv(name)
+			} else {
+				os.Setenv(name, *v)
+			}
+		}
+	}
+}
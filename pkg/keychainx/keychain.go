@@ -0,0 +1,110 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package keychainx
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Item describes stored credentials
+type Item struct {
+	Label string
+	User  string
+}
+
+// store is a credentials storage backend
+type store interface {
+	Save(label, user, password string) error
+	Load(label string) (string, string, error)
+	Remove(label string) error
+	List() ([]Item, error)
+	Close() error
+}
+
+// Save credentials with a given label
+func Save(label, user, password string) error {
+	s, err := open()
+	if err != nil {
+		return err
+	}
+
+	defer s.Close()
+
+	return s.Save(label, user, password)
+}
+
+// Load credentials with a given label
+func Load(label string) (string, string, error) {
+	s, err := open()
+	if err != nil {
+		return "", "", err
+	}
+
+	defer s.Close()
+
+	return s.Load(label)
+}
+
+// Remove credentials with a given label
+func Remove(label string) error {
+	s, err := open()
+	if err != nil {
+		return err
+	}
+
+	defer s.Close()
+
+	return s.Remove(label)
+}
+
+// List stored credentials, sorted by label
+func List() ([]Item, error) {
+	s, err := open()
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.Close()
+
+	items, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Label < items[j].Label
+	})
+
+	return items, nil
+}
+
+// open storage selected by GO_STARTER_KEYCHAIN environment variable: "native" for OS keychain, "file" for
+// encrypted file. By default OS keychain is used, falling back to encrypted file when it's not available.
+func open() (store, error) {
+	switch mode := os.Getenv("GO_STARTER_KEYCHAIN"); mode {
+	case "file":
+		return openFile()
+	case "native":
+		return openNative()
+	case "":
+		s, err := openNative()
+		if err == errUnavailable {
+			return openFile()
+		}
+
+		return s, err
+	default:
+		return nil, fmt.Errorf("unknown keychain %#v, must be one of: native, file", mode)
+	}
+}
@@ -17,21 +17,43 @@ import (
 	"github.com/keybase/go-keychain"
 )
 
-func Save(label, user, password string) error {
+// macKeychain stores credentials in macOS Keychain
+type macKeychain struct{}
+
+// openNative opens macOS Keychain
+func openNative() (store, error) {
+	return macKeychain{}, nil
+}
+
+// description marks items saved by go-starter
+const description = "go-starter"
+
+// Save credentials with a given label, replacing existing ones
+func (k macKeychain) Save(label, user, password string) error {
+	if err := k.Remove(label); err != nil && err != ErrNotFound {
+		return err
+	}
+
 	item := keychain.NewItem()
 	item.SetSecClass(keychain.SecClassInternetPassword)
 	item.SetLabel(label)
 	item.SetAccount(user)
+	item.SetDescription(description)
 	item.SetData([]byte(password))
 
 	return keychain.AddItem(item)
 }
 
-// Load credentials with a given label
-func Load(label string) (string, string, error) {
+// Load credentials with a given label saved by go-starter
+func (k macKeychain) Load(label string) (string, string, error) {
+	if err := k.adopt(label); err != nil {
+		return "", "", err
+	}
+
 	query := keychain.NewItem()
 	query.SetSecClass(keychain.SecClassInternetPassword)
 	query.SetLabel(label)
+	query.SetDescription(description)
 	query.SetMatchLimit(keychain.MatchLimitOne)
 	query.SetReturnAttributes(true)
 	query.SetReturnData(true)
@@ -47,3 +69,84 @@ func Load(label string) (string, string, error) {
 
 	return "", "", ErrNotFound
 }
+
+// Remove credentials with a given label saved by go-starter
+func (k macKeychain) Remove(label string) error {
+	if err := k.adopt(label); err != nil {
+		return err
+	}
+
+	item := keychain.NewItem()
+	item.SetSecClass(keychain.SecClassInternetPassword)
+	item.SetLabel(label)
+	item.SetDescription(description)
+
+	err := keychain.DeleteItem(item)
+	if err == keychain.ErrorItemNotFound {
+		return ErrNotFound
+	}
+
+	return err
+}
+
+// List credentials saved by go-starter
+func (macKeychain) List() ([]Item, error) {
+	query := keychain.NewItem()
+	query.SetSecClass(keychain.SecClassInternetPassword)
+	query.SetDescription(description)
+	query.SetMatchLimit(keychain.MatchLimitAll)
+	query.SetReturnAttributes(true)
+
+	results, err := keychain.QueryItem(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []Item
+	for _, r := range results {
+		items = append(items, Item{Label: r.Label, User: r.Account})
+	}
+
+	return items, nil
+}
+
+// adopt an item with a given label and no description (like one saved by earlier versions) when there is
+// no item saved by go-starter, so it is loaded, listed, replaced and removed as any other go-starter item
+func (macKeychain) adopt(label string) error {
+	query := keychain.NewItem()
+	query.SetSecClass(keychain.SecClassInternetPassword)
+	query.SetLabel(label)
+	query.SetDescription(description)
+	query.SetMatchLimit(keychain.MatchLimitOne)
+	query.SetReturnAttributes(true)
+
+	results, err := keychain.QueryItem(query)
+	if err != nil || len(results) > 0 {
+		return err
+	}
+
+	query = keychain.NewItem()
+	query.SetSecClass(keychain.SecClassInternetPassword)
+	query.SetLabel(label)
+	query.SetMatchLimit(keychain.MatchLimitOne)
+	query.SetReturnAttributes(true)
+
+	results, err = keychain.QueryItem(query)
+	if err != nil || len(results) == 0 {
+		return err
+	}
+
+	match := keychain.NewItem()
+	match.SetSecClass(keychain.SecClassInternetPassword)
+	match.SetLabel(label)
+	match.SetAccount(results[0].Account)
+
+	update := keychain.NewItem()
+	update.SetDescription(description)
+
+	return keychain.UpdateItem(match, update)
+}
+
+func (macKeychain) Close() error {
+	return nil
+}
@@ -0,0 +1,117 @@
+// +build darwin
+
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package keychainx
+
+import (
+	"github.com/keybase/go-keychain"
+	"testing"
+)
+
+func TestMacKeychainUndescribed(t *testing.T) {
+	const label = "go-starter-test.example.com"
+
+	cleanup := func() {
+		item := keychain.NewItem()
+		item.SetSecClass(keychain.SecClassInternetPassword)
+		item.SetLabel(label)
+		_ = keychain.DeleteItem(item)
+	}
+
+	cleanup()
+	defer cleanup()
+
+	// item saved by earlier versions has no description
+	item := keychain.NewItem()
+	item.SetSecClass(keychain.SecClassInternetPassword)
+	item.SetLabel(label)
+	item.SetAccount("octocat")
+	item.SetData([]byte("stale"))
+
+	if err := keychain.AddItem(item); err != nil {
+		t.Fatalf("AddItem() failed: %v", err)
+	}
+
+	k := macKeychain{}
+
+	if user, pass, err := k.Load(label); err != nil || user != "octocat" || pass != "stale" {
+		t.Errorf("Load() does not match: got %#v, %#v (%v), want %#v, %#v", user, pass, err, "octocat", "stale")
+	}
+
+	items, err := k.List()
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+
+	found := false
+	for _, i := range items {
+		if i.Label == label && i.User == "octocat" {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf("List() does not match: got %#v, want item with label %#v", items, label)
+	}
+
+	if err := k.Save(label, "octocat", "fresh"); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	if user, pass, err := k.Load(label); err != nil || user != "octocat" || pass != "fresh" {
+		t.Errorf("Load() does not match: got %#v, %#v (%v), want %#v, %#v", user, pass, err, "octocat", "fresh")
+	}
+
+	if err := k.Remove(label); err != nil {
+		t.Fatalf("Remove() failed: %v", err)
+	}
+
+	if _, _, err := k.Load(label); err != ErrNotFound {
+		t.Errorf("Load() after Remove() does not match: got %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestMacKeychainUndescribedSave(t *testing.T) {
+	const label = "go-starter-test-save.example.com"
+
+	cleanup := func() {
+		item := keychain.NewItem()
+		item.SetSecClass(keychain.SecClassInternetPassword)
+		item.SetLabel(label)
+		_ = keychain.DeleteItem(item)
+	}
+
+	cleanup()
+	defer cleanup()
+
+	item := keychain.NewItem()
+	item.SetSecClass(keychain.SecClassInternetPassword)
+	item.SetLabel(label)
+	item.SetAccount("octocat")
+	item.SetData([]byte("stale"))
+
+	if err := keychain.AddItem(item); err != nil {
+		t.Fatalf("AddItem() failed: %v", err)
+	}
+
+	// undescribed item with the same account is replaced instead of failing with duplicate item
+	k := macKeychain{}
+
+	if err := k.Save(label, "octocat", "fresh"); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	if user, pass, err := k.Load(label); err != nil || user != "octocat" || pass != "fresh" {
+		t.Errorf("Load() does not match: got %#v, %#v (%v), want %#v, %#v", user, pass, err, "octocat", "fresh")
+	}
+}
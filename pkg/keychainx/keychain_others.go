@@ -13,11 +13,12 @@ written permission of Adobe.
 
 package keychainx
 
-func Save(label, user, password string) error {
-	return nil
-}
+// openNative opens Secret Service (gnome-keyring, KWallet)
+func openNative() (store, error) {
+	s, err := openSecretService()
+	if err != nil {
+		return nil, err
+	}
 
-// Load credentials with a given label
-func Load(label string) (string, string, error) {
-	return "", "", ErrNotFound
+	return s, nil
 }
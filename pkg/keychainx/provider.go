@@ -0,0 +1,156 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package keychainx
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Provider is a source of credentials, it returns ErrNotFound when it has no credentials with a given label
+type Provider interface {
+	Credentials(label string) (string, string, error)
+}
+
+// ProviderFunc is an adapter to use ordinary function as a Provider
+type ProviderFunc func(label string) (string, string, error)
+
+// Credentials calls f(label)
+func (f ProviderFunc) Credentials(label string) (string, string, error) {
+	return f(label)
+}
+
+// Chain of providers, credentials are taken from the first provider which has them
+type Chain []Provider
+
+// Credentials from the first provider which has them
+func (c Chain) Credentials(label string) (string, string, error) {
+	for _, p := range c {
+		user, pass, err := p.Credentials(label)
+		if err == ErrNotFound {
+			continue
+		}
+
+		return user, pass, err
+	}
+
+	return "", "", ErrNotFound
+}
+
+// Env provides token from environment variable with a given name, user is empty
+type Env string
+
+// Credentials from environment variable
+func (e Env) Credentials(label string) (string, string, error) {
+	if token := os.Getenv(string(e)); token != "" {
+		return "", token, nil
+	}
+
+	return "", "", ErrNotFound
+}
+
+// File provides token from a file with a given path, user is empty. Empty path provides no credentials.
+type File string
+
+// Credentials from file
+func (f File) Credentials(label string) (string, string, error) {
+	if f == "" {
+		return "", "", ErrNotFound
+	}
+
+	data, err := ioutil.ReadFile(string(f))
+	if err != nil {
+		return "", "", fmt.Errorf("unable to read token file: %v", err)
+	}
+
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", "", fmt.Errorf("token file %v is empty", f)
+	}
+
+	return "", token, nil
+}
+
+// GitCredential provides credentials stored by git credential helpers, label is used as a host name
+type GitCredential struct{}
+
+// Credentials from "git credential fill", git is not allowed to prompt user
+func (GitCredential) Credentials(label string) (string, string, error) {
+	cmd := exec.Command("git", "credential", "fill")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("protocol=https\nhost=%v\n\n", label))
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "SSH_ASKPASS=", "GCM_INTERACTIVE=never")
+
+	out, err := cmd.Output()
+	if err != nil {
+		// git is not installed or there is no credential helper which knows the host
+		return "", "", ErrNotFound
+	}
+
+	values := map[string]string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if parts := strings.SplitN(scanner.Text(), "=", 2); len(parts) == 2 {
+			values[parts[0]] = parts[1]
+		}
+	}
+
+	if values["password"] == "" {
+		return "", "", ErrNotFound
+	}
+
+	return values["username"], values["password"], nil
+}
+
+// Verified checks credentials of the wrapped provider. Credentials rejected by Verify (it returns ErrRejected)
+// are reported to Rejected callback and treated as not found, so the next provider in the chain is used.
+type Verified struct {
+	Provider Provider
+	Verify   func(label, user, pass string) error
+	Rejected func(label string)
+}
+
+// Credentials of the wrapped provider, if they are valid
+func (v Verified) Credentials(label string) (string, string, error) {
+	user, pass, err := v.Provider.Credentials(label)
+	if err != nil {
+		return "", "", err
+	}
+
+	err = v.Verify(label, user, pass)
+	if err == ErrRejected {
+		if v.Rejected != nil {
+			v.Rejected(label)
+		}
+
+		return "", "", ErrNotFound
+	}
+
+	if err != nil {
+		return "", "", err
+	}
+
+	return user, pass, nil
+}
+
+// Keychain provides credentials stored in keychain
+type Keychain struct{}
+
+// Credentials loaded from keychain
+func (Keychain) Credentials(label string) (string, string, error) {
+	return Load(label)
+}
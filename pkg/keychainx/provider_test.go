@@ -0,0 +1,160 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package keychainx
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	failure := errors.New("failure")
+
+	none := ProviderFunc(func(string) (string, string, error) { return "", "", ErrNotFound })
+	broken := ProviderFunc(func(string) (string, string, error) { return "", "", failure })
+	valid := ProviderFunc(func(label string) (string, string, error) { return "user", label, nil })
+
+	tests := []struct {
+		name  string
+		chain Chain
+		pass  string
+		err   error
+	}{
+		{name: "empty", chain: Chain{}, err: ErrNotFound},
+		{name: "none", chain: Chain{none, none}, err: ErrNotFound},
+		{name: "first", chain: Chain{valid, broken}, pass: "github.com"},
+		{name: "skip", chain: Chain{none, valid}, pass: "github.com"},
+		{name: "error", chain: Chain{none, broken, valid}, err: failure},
+	}
+
+	for _, test := range tests {
+		_, pass, err := test.chain.Credentials("github.com")
+		if err != test.err {
+			t.Errorf("Error of %v does not match: got %v, want %v", test.name, err, test.err)
+		}
+
+		if pass != test.pass {
+			t.Errorf("Password of %v does not match: got %#v, want %#v", test.name, pass, test.pass)
+		}
+	}
+}
+
+func TestEnv(t *testing.T) {
+	defer os.Unsetenv("KEYCHAINX_TEST_TOKEN")
+
+	if _, _, err := Env("KEYCHAINX_TEST_TOKEN").Credentials("github.com"); err != ErrNotFound {
+		t.Errorf("Error does not match: got %v, want %v", err, ErrNotFound)
+	}
+
+	os.Setenv("KEYCHAINX_TEST_TOKEN", "token")
+
+	if _, pass, err := Env("KEYCHAINX_TEST_TOKEN").Credentials("github.com"); err != nil || pass != "token" {
+		t.Errorf("Credentials do not match: got %#v (%v), want %#v", pass, err, "token")
+	}
+}
+
+func TestFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keychainx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(path, []byte("token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := File("").Credentials("github.com"); err != ErrNotFound {
+		t.Errorf("Error does not match: got %v, want %v", err, ErrNotFound)
+	}
+
+	if _, pass, err := File(path).Credentials("github.com"); err != nil || pass != "token" {
+		t.Errorf("Credentials do not match: got %#v (%v), want %#v", pass, err, "token")
+	}
+
+	if _, _, err := File(path + ".missing").Credentials("github.com"); err == nil || err == ErrNotFound {
+		t.Errorf("Missing token file must fail, got %v", err)
+	}
+}
+
+func TestGitCredential(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	dir, err := ioutil.TempDir("", "keychainx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	config := "[credential \"https://example.com\"]\n\thelper = \"!f() { echo username=octocat; echo password=token; }; f\"\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, ".gitconfig"), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"HOME", "XDG_CONFIG_HOME", "GIT_CONFIG_NOSYSTEM"} {
+		defer os.Setenv(name, os.Getenv(name))
+	}
+
+	os.Setenv("HOME", dir)
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	os.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	user, pass, err := GitCredential{}.Credentials("example.com")
+	if err != nil || user != "octocat" || pass != "token" {
+		t.Errorf("Credentials do not match: got %#v (%v), want %#v", []string{user, pass}, err, []string{"octocat", "token"})
+	}
+
+	if _, _, err := (GitCredential{}).Credentials("unknown.example.com"); err != ErrNotFound {
+		t.Errorf("Error does not match: got %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestVerified(t *testing.T) {
+	failure := errors.New("failure")
+	valid := ProviderFunc(func(label string) (string, string, error) { return "user", label, nil })
+
+	tests := []struct {
+		name     string
+		verify   error
+		pass     string
+		err      error
+		rejected bool
+	}{
+		{name: "valid", verify: nil, pass: "github.com"},
+		{name: "rejected", verify: ErrRejected, err: ErrNotFound, rejected: true},
+		{name: "failure", verify: failure, err: failure},
+	}
+
+	for _, test := range tests {
+		rejected := false
+
+		p := Verified{
+			Provider: valid,
+			Verify:   func(label, user, pass string) error { return test.verify },
+			Rejected: func(label string) { rejected = true },
+		}
+
+		_, pass, err := p.Credentials("github.com")
+		if err != test.err || pass != test.pass || rejected != test.rejected {
+			t.Errorf("Result of %v does not match: got %#v, %v, %v, want %#v, %v, %v", test.name, pass, err, rejected, test.pass, test.err, test.rejected)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package keychainx
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// Secret passed to CI pipelines of the repository
+type Secret struct {
+	Name  string
+	Value string
+}
+
+// ReadSecrets resolves secrets passed with flags: name=value literals, name=path files and name=path#field
+// HashiCorp Vault references. Vault client is configured with VAULT_* environment variables when it's needed.
+func ReadSecrets(literals, files, vault []string) ([]Secret, error) {
+	var secrets []Secret
+
+	for _, s := range literals {
+		name, value := splitKeyValue(s)
+		secrets = append(secrets, Secret{Name: name, Value: value})
+	}
+
+	for _, s := range files {
+		name, file := splitKeyValue(s)
+
+		value, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read secret file %#v: %v", file, err)
+		}
+
+		secrets = append(secrets, Secret{Name: name, Value: string(value)})
+	}
+
+	if len(vault) == 0 {
+		return secrets, nil
+	}
+
+	client, err := NewVaultClient()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range vault {
+		name, ref := splitKeyValue(s)
+
+		value, err := ReadVault(client, ref)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read secret %#v from Vault: %v", ref, err)
+		}
+
+		secrets = append(secrets, Secret{Name: name, Value: value})
+	}
+
+	return secrets, nil
+}
+
+// splitKeyValue splits "name=value" flag, value is empty when there is no "="
+func splitKeyValue(s string) (string, string) {
+	if parts := strings.SplitN(s, "=", 2); len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+
+	return s, ""
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package keychainx
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestReadSecrets(t *testing.T) {
+	server := vaultServer()
+	defer server.Close()
+
+	defer restoreEnv("VAULT_ADDR", "VAULT_TOKEN")()
+
+	os.Setenv("VAULT_ADDR", server.URL)
+	os.Setenv("VAULT_TOKEN", "root")
+
+	file, err := ioutil.TempFile("", "keychainx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(file.Name())
+
+	_, _ = file.WriteString("from file")
+	_ = file.Close()
+
+	secrets, err := ReadSecrets([]string{"A=literal=value"}, []string{"B=" + file.Name()}, []string{"C=kv/drone"})
+	if err != nil {
+		t.Fatalf("ReadSecrets() failed: %v", err)
+	}
+
+	want := []Secret{{Name: "A", Value: "literal=value"}, {Name: "B", Value: "from file"}, {Name: "C", Value: "v1-token"}}
+	if !reflect.DeepEqual(secrets, want) {
+		t.Errorf("Secrets do not match: got %#v, want %#v", secrets, want)
+	}
+
+	if _, err := ReadSecrets(nil, []string{"C=/nonexistent"}, nil); err == nil {
+		t.Errorf("ReadSecrets() with missing file must fail")
+	}
+
+	if _, err := ReadSecrets(nil, nil, []string{"D=kv/missing"}); err == nil {
+		t.Errorf("ReadSecrets() with missing Vault secret must fail")
+	}
+}
@@ -0,0 +1,325 @@
+// +build !darwin
+
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package keychainx
+
+import (
+	"fmt"
+	"github.com/godbus/dbus/v5"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	secretsName        = "org.freedesktop.secrets"
+	secretsPath        = dbus.ObjectPath("/org/freedesktop/secrets")
+	secretsCollection  = dbus.ObjectPath("/org/freedesktop/secrets/aliases/default")
+	secretsService     = "org.freedesktop.Secret.Service"
+	secretsItem        = "org.freedesktop.Secret.Item"
+	secretsPrompt      = "org.freedesktop.Secret.Prompt"
+	secretsNoPrompt    = dbus.ObjectPath("/")
+	secretsApplication = "go-starter"
+)
+
+// promptTimeout limits time to wait for user to unlock the keyring
+var promptTimeout = 2 * time.Minute
+
+// secret as defined by Secret Service API
+type secret struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+// secretService is a client of freedesktop.org Secret Service API, implemented by gnome-keyring and KWallet
+type secretService struct {
+	conn    *dbus.Conn
+	session dbus.ObjectPath
+}
+
+// openSecretService connects to the session bus and opens Secret Service session
+func openSecretService() (*secretService, error) {
+	address := os.Getenv("DBUS_SESSION_BUS_ADDRESS")
+	if address == "" {
+		// do not let dbus library autolaunch a new bus, there would be no keyring on it anyway
+		path := filepath.Join(os.Getenv("XDG_RUNTIME_DIR"), "bus")
+		if _, err := os.Stat(path); os.Getenv("XDG_RUNTIME_DIR") == "" || err != nil {
+			return nil, errUnavailable
+		}
+
+		address = "unix:path=" + path
+	}
+
+	conn, err := dbus.Dial(address)
+	if err != nil {
+		return nil, errUnavailable
+	}
+
+	if err := conn.Auth(nil); err != nil {
+		_ = conn.Close()
+		return nil, errUnavailable
+	}
+
+	if err := conn.Hello(); err != nil {
+		_ = conn.Close()
+		return nil, errUnavailable
+	}
+
+	s, err := newSecretService(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// newSecretService opens a session using given connection
+func newSecretService(conn *dbus.Conn) (*secretService, error) {
+	var output dbus.Variant
+	var session dbus.ObjectPath
+
+	err := conn.Object(secretsName, secretsPath).
+		Call(secretsService+".OpenSession", 0, "plain", dbus.MakeVariant("")).
+		Store(&output, &session)
+
+	if err != nil {
+		return nil, errUnavailable
+	}
+
+	return &secretService{conn: conn, session: session}, nil
+}
+
+// Close connection to the session bus
+func (s *secretService) Close() error {
+	return s.conn.Close()
+}
+
+// Save credentials with a given label, replacing existing ones
+func (s *secretService) Save(label, user, password string) error {
+	if err := s.Remove(label); err != nil && err != ErrNotFound {
+		return err
+	}
+
+	props := map[string]dbus.Variant{
+		secretsItem + ".Label": dbus.MakeVariant(label),
+		secretsItem + ".Attributes": dbus.MakeVariant(map[string]string{
+			"application": secretsApplication,
+			"label":       label,
+			"account":     user,
+		}),
+	}
+
+	value := secret{
+		Session:     s.session,
+		Parameters:  []byte{},
+		Value:       []byte(password),
+		ContentType: "text/plain",
+	}
+
+	if _, err := s.unlock([]dbus.ObjectPath{secretsCollection}); err != nil {
+		return err
+	}
+
+	var item, prompt dbus.ObjectPath
+
+	err := s.conn.Object(secretsName, secretsCollection).
+		Call("org.freedesktop.Secret.Collection.CreateItem", 0, props, value, true).
+		Store(&item, &prompt)
+
+	if err != nil {
+		return fmt.Errorf("unable to create secret: %v", err)
+	}
+
+	_, err = s.prompt(prompt)
+	return err
+}
+
+// Load credentials with a given label
+func (s *secretService) Load(label string) (string, string, error) {
+	items, err := s.search(label)
+	if err != nil {
+		return "", "", err
+	}
+
+	if len(items) == 0 {
+		return "", "", ErrNotFound
+	}
+
+	item := s.conn.Object(secretsName, items[0])
+
+	var value secret
+	if err := item.Call(secretsItem+".GetSecret", 0, s.session).Store(&value); err != nil {
+		return "", "", fmt.Errorf("unable to read secret: %v", err)
+	}
+
+	attributes, err := item.GetProperty(secretsItem + ".Attributes")
+	if err != nil {
+		return "", "", fmt.Errorf("unable to read secret attributes: %v", err)
+	}
+
+	account, _ := attributes.Value().(map[string]string)
+
+	return account["account"], string(value.Value), nil
+}
+
+// Remove credentials with a given label
+func (s *secretService) Remove(label string) error {
+	items, err := s.search(label)
+	if err != nil {
+		return err
+	}
+
+	if len(items) == 0 {
+		return ErrNotFound
+	}
+
+	for _, path := range items {
+		var prompt dbus.ObjectPath
+		if err := s.conn.Object(secretsName, path).Call(secretsItem+".Delete", 0).Store(&prompt); err != nil {
+			return fmt.Errorf("unable to delete secret: %v", err)
+		}
+
+		if _, err := s.prompt(prompt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// List credentials saved by go-starter
+func (s *secretService) List() ([]Item, error) {
+	items, err := s.search("")
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Item
+	for _, path := range items {
+		attributes, err := s.conn.Object(secretsName, path).GetProperty(secretsItem + ".Attributes")
+		if err != nil {
+			return nil, fmt.Errorf("unable to read secret attributes: %v", err)
+		}
+
+		values, _ := attributes.Value().(map[string]string)
+		list = append(list, Item{Label: values["label"], User: values["account"]})
+	}
+
+	return list, nil
+}
+
+// search items with a given label (or all items saved by go-starter when label is empty), unlocking them if needed
+func (s *secretService) search(label string) ([]dbus.ObjectPath, error) {
+	var unlocked, locked []dbus.ObjectPath
+
+	attributes := map[string]string{"application": secretsApplication}
+	if label != "" {
+		attributes["label"] = label
+	}
+
+	err := s.conn.Object(secretsName, secretsPath).
+		Call(secretsService+".SearchItems", 0, attributes).
+		Store(&unlocked, &locked)
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to search secrets: %v", err)
+	}
+
+	if len(locked) > 0 {
+		more, err := s.unlock(locked)
+		if err != nil {
+			return nil, err
+		}
+
+		unlocked = append(unlocked, more...)
+	}
+
+	return unlocked, nil
+}
+
+// unlock objects, asking user to enter keyring password if needed
+func (s *secretService) unlock(objects []dbus.ObjectPath) ([]dbus.ObjectPath, error) {
+	var unlocked []dbus.ObjectPath
+	var prompt dbus.ObjectPath
+
+	err := s.conn.Object(secretsName, secretsPath).
+		Call(secretsService+".Unlock", 0, objects).
+		Store(&unlocked, &prompt)
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to unlock keyring: %v", err)
+	}
+
+	result, err := s.prompt(prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	if paths, ok := result.Value().([]dbus.ObjectPath); ok {
+		unlocked = append(unlocked, paths...)
+	}
+
+	return unlocked, nil
+}
+
+// prompt user and wait for the prompt to complete, if prompt is required
+func (s *secretService) prompt(path dbus.ObjectPath) (dbus.Variant, error) {
+	if path == secretsNoPrompt || path == "" {
+		return dbus.MakeVariant(""), nil
+	}
+
+	match := []dbus.MatchOption{
+		dbus.WithMatchObjectPath(path),
+		dbus.WithMatchInterface(secretsPrompt),
+		dbus.WithMatchMember("Completed"),
+	}
+
+	if err := s.conn.AddMatchSignal(match...); err != nil {
+		return dbus.Variant{}, err
+	}
+
+	defer func() {
+		_ = s.conn.RemoveMatchSignal(match...)
+	}()
+
+	signals := make(chan *dbus.Signal, 1)
+	s.conn.Signal(signals)
+	defer s.conn.RemoveSignal(signals)
+
+	if err := s.conn.Object(secretsName, path).Call(secretsPrompt+".Prompt", 0, "").Err; err != nil {
+		return dbus.Variant{}, fmt.Errorf("unable to prompt for keyring password: %v", err)
+	}
+
+	timeout := time.After(promptTimeout)
+
+	for {
+		select {
+		case signal := <-signals:
+			if signal.Path != path || signal.Name != secretsPrompt+".Completed" || len(signal.Body) < 2 {
+				continue
+			}
+
+			if dismissed, _ := signal.Body[0].(bool); dismissed {
+				return dbus.Variant{}, fmt.Errorf("keyring prompt has been dismissed")
+			}
+
+			result, _ := signal.Body[1].(dbus.Variant)
+			return result, nil
+		case <-timeout:
+			return dbus.Variant{}, fmt.Errorf("keyring prompt timed out")
+		}
+	}
+}
@@ -0,0 +1,302 @@
+// +build !darwin
+
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package keychainx
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/godbus/dbus/v5"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeSecrets is an in-memory implementation of the subset of Secret Service API used by keychainx
+type fakeSecrets struct {
+	mu     sync.Mutex
+	conn   *dbus.Conn
+	items  map[dbus.ObjectPath]*fakeItem
+	next   int
+	locked bool
+}
+
+type fakeItem struct {
+	secrets    *fakeSecrets
+	path       dbus.ObjectPath
+	attributes map[string]string
+	value      []byte
+}
+
+type fakeProperties struct {
+	item *fakeItem
+}
+
+type fakePrompt struct {
+	secrets *fakeSecrets
+	path    dbus.ObjectPath
+	objects []dbus.ObjectPath
+}
+
+func (f *fakeSecrets) OpenSession(algorithm string, input dbus.Variant) (dbus.Variant, dbus.ObjectPath, *dbus.Error) {
+	if algorithm != "plain" {
+		return dbus.Variant{}, "", dbus.MakeFailedError(fmt.Errorf("unsupported algorithm %v", algorithm))
+	}
+
+	return dbus.MakeVariant(""), "/org/freedesktop/secrets/session/1", nil
+}
+
+func (f *fakeSecrets) SearchItems(attributes map[string]string) ([]dbus.ObjectPath, []dbus.ObjectPath, *dbus.Error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	found := []dbus.ObjectPath{}
+
+	for path, item := range f.items {
+		match := true
+		for k, v := range attributes {
+			if item.attributes[k] != v {
+				match = false
+			}
+		}
+
+		if match {
+			found = append(found, path)
+		}
+	}
+
+	if f.locked {
+		return []dbus.ObjectPath{}, found, nil
+	}
+
+	return found, []dbus.ObjectPath{}, nil
+}
+
+func (f *fakeSecrets) Unlock(objects []dbus.ObjectPath) ([]dbus.ObjectPath, dbus.ObjectPath, *dbus.Error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.locked {
+		return objects, secretsNoPrompt, nil
+	}
+
+	f.next++
+	prompt := &fakePrompt{secrets: f, path: dbus.ObjectPath(fmt.Sprintf("/org/freedesktop/secrets/prompt/%d", f.next)), objects: objects}
+
+	if err := f.conn.Export(prompt, prompt.path, secretsPrompt); err != nil {
+		return nil, "", dbus.MakeFailedError(err)
+	}
+
+	return []dbus.ObjectPath{}, prompt.path, nil
+}
+
+func (f *fakeSecrets) CreateItem(props map[string]dbus.Variant, value secret, replace bool) (dbus.ObjectPath, dbus.ObjectPath, *dbus.Error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	attributes, _ := props[secretsItem+".Attributes"].Value().(map[string]string)
+
+	f.next++
+	item := &fakeItem{
+		secrets:    f,
+		path:       dbus.ObjectPath(fmt.Sprintf("/org/freedesktop/secrets/collection/login/%d", f.next)),
+		attributes: attributes,
+		value:      value.Value,
+	}
+
+	if err := f.conn.Export(item, item.path, secretsItem); err != nil {
+		return "", "", dbus.MakeFailedError(err)
+	}
+
+	if err := f.conn.Export(&fakeProperties{item: item}, item.path, "org.freedesktop.DBus.Properties"); err != nil {
+		return "", "", dbus.MakeFailedError(err)
+	}
+
+	f.items[item.path] = item
+
+	return item.path, secretsNoPrompt, nil
+}
+
+func (i *fakeItem) GetSecret(session dbus.ObjectPath) (secret, *dbus.Error) {
+	return secret{Session: session, Parameters: []byte{}, Value: i.value, ContentType: "text/plain"}, nil
+}
+
+func (i *fakeItem) Delete() (dbus.ObjectPath, *dbus.Error) {
+	i.secrets.mu.Lock()
+	defer i.secrets.mu.Unlock()
+
+	delete(i.secrets.items, i.path)
+	_ = i.secrets.conn.Export(nil, i.path, secretsItem)
+	_ = i.secrets.conn.Export(nil, i.path, "org.freedesktop.DBus.Properties")
+
+	return secretsNoPrompt, nil
+}
+
+func (p *fakeProperties) Get(iface, name string) (dbus.Variant, *dbus.Error) {
+	if iface == secretsItem && name == "Attributes" {
+		return dbus.MakeVariant(p.item.attributes), nil
+	}
+
+	return dbus.Variant{}, dbus.MakeFailedError(fmt.Errorf("unknown property %v.%v", iface, name))
+}
+
+func (p *fakePrompt) Prompt(window string) *dbus.Error {
+	p.secrets.mu.Lock()
+	defer p.secrets.mu.Unlock()
+
+	p.secrets.locked = false
+
+	go func() {
+		_ = p.secrets.conn.Emit(p.path, secretsPrompt+".Completed", false, dbus.MakeVariant(p.objects))
+	}()
+
+	return nil
+}
+
+// startBus starts a private session bus with a fake secret service and returns a client connection to it
+func startBus(t *testing.T) (*fakeSecrets, *dbus.Conn, func()) {
+	path, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon is not available")
+	}
+
+	cmd := exec.Command(path, "--session", "--nofork", "--nopidfile", "--print-address")
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		t.Skipf("unable to start dbus-daemon: %v", err)
+	}
+
+	var conns []*dbus.Conn
+
+	stop := func() {
+		for _, conn := range conns {
+			_ = conn.Close()
+		}
+
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}
+
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		stop()
+		t.Fatalf("unable to read bus address: %v", err)
+	}
+
+	connect := func() *dbus.Conn {
+		conn, err := dbus.Dial(strings.TrimSpace(address))
+		if err == nil {
+			conns = append(conns, conn)
+			err = conn.Auth(nil)
+		}
+
+		if err == nil {
+			err = conn.Hello()
+		}
+
+		if err != nil {
+			stop()
+			t.Fatalf("unable to connect to the bus: %v", err)
+		}
+
+		return conn
+	}
+
+	fake := &fakeSecrets{conn: connect(), items: map[dbus.ObjectPath]*fakeItem{}}
+
+	err = fake.conn.Export(fake, secretsPath, secretsService)
+	if err == nil {
+		err = fake.conn.Export(fake, secretsCollection, "org.freedesktop.Secret.Collection")
+	}
+
+	if err == nil {
+		_, err = fake.conn.RequestName(secretsName, dbus.NameFlagDoNotQueue)
+	}
+
+	if err != nil {
+		stop()
+		t.Fatalf("unable to start fake secret service: %v", err)
+	}
+
+	return fake, connect(), stop
+}
+
+func TestSecretService(t *testing.T) {
+	fake, conn, stop := startBus(t)
+	defer stop()
+
+	s, err := newSecretService(conn)
+	if err != nil {
+		t.Fatalf("Unable to open session: %v", err)
+	}
+
+	if _, _, err := s.Load("github.com"); err != ErrNotFound {
+		t.Errorf("Load() error does not match: got %v, want %v", err, ErrNotFound)
+	}
+
+	if err := s.Save("github.com", "user", "secret"); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	if err := s.Save("github.com", "user", "token"); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	if err := s.Save("drone.io", "admin", "other"); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	if len(fake.items) != 2 {
+		t.Errorf("Number of items does not match: got %v, want %v", len(fake.items), 2)
+	}
+
+	fake.mu.Lock()
+	fake.locked = true
+	fake.mu.Unlock()
+
+	user, password, err := s.Load("github.com")
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if user != "user" || password != "token" {
+		t.Errorf("Credentials do not match: got %#v, want %#v", []string{user, password}, []string{"user", "token"})
+	}
+
+	items, err := s.List()
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Errorf("Number of items does not match: got %#v, want %v", items, 2)
+	}
+
+	if err := s.Remove("github.com"); err != nil {
+		t.Fatalf("Remove() failed: %v", err)
+	}
+
+	if _, _, err := s.Load("github.com"); err != ErrNotFound {
+		t.Errorf("Load() error does not match: got %v, want %v", err, ErrNotFound)
+	}
+
+	if err := s.Remove("github.com"); err != ErrNotFound {
+		t.Errorf("Remove() error does not match: got %v, want %v", err, ErrNotFound)
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package keychainx
+
+import (
+	"fmt"
+	"github.com/hashicorp/vault/api"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Vault provides token stored in HashiCorp Vault KV secrets engine (version 1 or 2), user is empty.
+// Path is in format "secret/path#field", field can be omitted when secret has only one field.
+// Empty path provides no credentials.
+type Vault struct {
+	// Client to use, when it's nil client is configured with VAULT_* environment variables
+	Client *api.Client
+	Path   string
+}
+
+// Credentials from Vault
+func (v Vault) Credentials(label string) (string, string, error) {
+	if v.Path == "" {
+		return "", "", ErrNotFound
+	}
+
+	client := v.Client
+	if client == nil {
+		var err error
+		if client, err = NewVaultClient(); err != nil {
+			return "", "", err
+		}
+	}
+
+	token, err := ReadVault(client, v.Path)
+	return "", token, err
+}
+
+// NewVaultClient configured with VAULT_* environment variables, token is read from ~/.vault-token (written by
+// "vault login") when VAULT_TOKEN is not set
+func NewVaultClient() (*api.Client, error) {
+	client, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		return nil, fmt.Errorf("unable to configure Vault client: %v", err)
+	}
+
+	if client.Token() == "" {
+		if data, err := ioutil.ReadFile(filepath.Join(os.Getenv("HOME"), ".vault-token")); err == nil {
+			client.SetToken(strings.TrimSpace(string(data)))
+		}
+	}
+
+	return client, nil
+}
+
+// ReadVault reads a field of the secret, reference is in format "secret/path#field"
+func ReadVault(client *api.Client, ref string) (string, error) {
+	path, field := ref, ""
+	if i := strings.LastIndex(ref, "#"); i >= 0 {
+		path, field = ref[:i], ref[i+1:]
+	}
+
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return "", fmt.Errorf("vault secret path is empty")
+	}
+
+	mount, version, err := vaultMount(client, path)
+	if err != nil {
+		return "", err
+	}
+
+	read := path
+	if version == 2 {
+		read = mount + "data/" + strings.TrimPrefix(path, mount)
+	}
+
+	secret, err := client.Logical().Read(read)
+	if err != nil {
+		return "", fmt.Errorf("unable to read Vault secret %v: %v", path, err)
+	}
+
+	if secret == nil || secret.Data == nil {
+		return "", fmt.Errorf("vault secret %v not found", path)
+	}
+
+	data := secret.Data
+	if version == 2 {
+		data, _ = secret.Data["data"].(map[string]interface{})
+		if data == nil {
+			return "", fmt.Errorf("vault secret %v not found", path)
+		}
+	}
+
+	if field == "" {
+		if len(data) != 1 {
+			return "", fmt.Errorf("vault secret %v has %v fields, specify field as %v#field", path, len(data), path)
+		}
+
+		for k := range data {
+			field = k
+		}
+	}
+
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("vault secret %v has no %#v field", path, field)
+	}
+
+	return fmt.Sprint(value), nil
+}
+
+// vaultMount returns mount path (with trailing slash) and version of the KV secrets engine
+func vaultMount(client *api.Client, path string) (string, int, error) {
+	r := client.NewRequest("GET", "/v1/sys/internal/ui/mounts/"+path)
+
+	resp, err := client.RawRequest(r)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		// older Vault versions have no mounts endpoint, they support version 1 only
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return "", 1, nil
+		}
+
+		return "", 0, fmt.Errorf("unable to read Vault mount of %v: %v", path, err)
+	}
+
+	secret, err := api.ParseSecret(resp.Body)
+	if err != nil {
+		return "", 0, fmt.Errorf("unable to read Vault mount of %v: %v", path, err)
+	}
+
+	if secret == nil || secret.Data == nil {
+		return "", 1, nil
+	}
+
+	mount, _ := secret.Data["path"].(string)
+
+	if options, ok := secret.Data["options"].(map[string]interface{}); ok && fmt.Sprint(options["version"]) == "2" {
+		return mount, 2, nil
+	}
+
+	return mount, 1, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package keychainx
+
+import (
+	"github.com/hashicorp/vault/api"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// vaultServer emulates Vault with "secret/" KV version 2 and "kv/" KV version 1 mounts
+func vaultServer() *httptest.Server {
+	responses := map[string]string{
+		"/v1/sys/internal/ui/mounts/secret/go-starter/github": `{"data": {"path": "secret/", "type": "kv", "options": {"version": "2"}}}`,
+		"/v1/sys/internal/ui/mounts/kv/drone":                 `{"data": {"path": "kv/", "type": "kv", "options": null}}`,
+		"/v1/secret/data/go-starter/github":                   `{"data": {"data": {"token": "v2-token", "user": "octocat"}, "metadata": {"version": 1}}}`,
+		"/v1/kv/drone":                                        `{"data": {"token": "v1-token"}}`,
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors": ["permission denied"]}`))
+			return
+		}
+
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors": []}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func TestReadVault(t *testing.T) {
+	server := vaultServer()
+	defer server.Close()
+
+	config := api.DefaultConfig()
+	config.Address = server.URL
+
+	client, err := api.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client.SetToken("root")
+
+	tests := []struct {
+		ref   string
+		value string
+		fails bool
+	}{
+		{ref: "secret/go-starter/github#token", value: "v2-token"},
+		{ref: "secret/go-starter/github#user", value: "octocat"},
+		{ref: "/secret/go-starter/github/#token", value: "v2-token"},
+		{ref: "kv/drone#token", value: "v1-token"},
+		{ref: "kv/drone", value: "v1-token"},
+		{ref: "secret/go-starter/github", fails: true},
+		{ref: "secret/go-starter/github#missing", fails: true},
+		{ref: "kv/unknown#token", fails: true},
+		{ref: "#token", fails: true},
+	}
+
+	for _, test := range tests {
+		value, err := ReadVault(client, test.ref)
+		if (err != nil) != test.fails {
+			t.Errorf("Error of %v does not match: got %v, want failure %v", test.ref, err, test.fails)
+		}
+
+		if value != test.value {
+			t.Errorf("Value of %v does not match: got %#v, want %#v", test.ref, value, test.value)
+		}
+	}
+
+	_, pass, err := Vault{Client: client, Path: "kv/drone#token"}.Credentials("drone.io")
+	if err != nil || pass != "v1-token" {
+		t.Errorf("Credentials do not match: got %#v (%v), want %#v", pass, err, "v1-token")
+	}
+
+	if _, _, err := (Vault{Client: client}).Credentials("drone.io"); err != ErrNotFound {
+		t.Errorf("Error does not match: got %v, want %v", err, ErrNotFound)
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package maker
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"strings"
+)
+
+// LoadAnswers from YAML or JSON file, where keys are question names
+func LoadAnswers(file string) (Vars, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("unable to parse answers file %#v: %v", file, err)
+	}
+
+	answers := make(Vars)
+	for k, v := range raw {
+		switch value := v.(type) {
+		case nil:
+			answers[k] = ""
+		case []interface{}:
+			items := make([]string, 0, len(value))
+			for _, item := range value {
+				items = append(items, fmt.Sprint(item))
+			}
+
+			answers[k] = strings.Join(items, ",")
+		default:
+			answers[k] = fmt.Sprint(value)
+		}
+	}
+
+	return answers, nil
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package maker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadAnswers(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "answers.yml", content: "name: awesome\nport: 8080\nprivate: true\nempty: ~\nowners: [foo, bar]\n"},
+		{name: "answers.json", content: `{"name": "awesome", "port": 8080, "private": true, "empty": null, "owners": ["foo", "bar"]}`},
+	}
+
+	want := Vars{"name": "awesome", "port": "8080", "private": "true", "empty": "", "owners": "foo,bar"}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "answers")
+			if err != nil {
+				t.Fatalf("Unable to create temporary dir: %v", err)
+			}
+			defer os.RemoveAll(dir)
+
+			file := filepath.Join(dir, test.name)
+			if err := ioutil.WriteFile(file, []byte(test.content), 0666); err != nil {
+				t.Fatalf("Unable to create answers file: %v", err)
+			}
+
+			got, err := LoadAnswers(file)
+			if err != nil {
+				t.Fatalf("LoadAnswers should not return an error, but it returned %v", err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Answers do not match: got %#v, want %#v", got, want)
+			}
+		})
+	}
+}
@@ -14,6 +14,17 @@ package maker
 import (
 	"fmt"
 	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Question types
+const (
+	Input       = "input"
+	Select      = "select"
+	MultiSelect = "multiselect"
+	Confirm     = "confirm"
+	Password    = "password"
 )
 
 type console interface {
@@ -21,6 +32,7 @@ type console interface {
 	Printf(format string, args ...interface{})
 	Errorf(format string, args ...interface{})
 	ReadString(string) string
+	ReadPassword(string) string
 }
 
 // Ask questions from questions.yml
@@ -30,10 +42,19 @@ func Ask(ui console, questions []Question, vars map[string]string) (map[string]s
 	answers := vars
 
 	for _, q := range questions {
+		// skip question if condition is not met
+		if ok, err := When(q.When, answers); err != nil || !ok {
+			if err != nil {
+				return nil, fmt.Errorf("unable to evaluate condition of question %v: %v", q.Name, err)
+			}
+
+			continue
+		}
+
 		// check if there is valid value in vars
 		if v, ok := vars[q.Name]; ok {
 			if ok, _ := Valid(q, v); ok {
-				answers[q.Name] = v
+				answers[q.Name] = Normalize(q, v)
 				continue
 			}
 
@@ -48,11 +69,27 @@ func Ask(ui console, questions []Question, vars map[string]string) (map[string]s
 				ui.Printf("Help: %v\n", q.HelpMessage)
 			}
 
-			if q.Default != "" {
+			for i, option := range q.Options {
+				ui.Printf("  %v) %v\n", i+1, option)
+			}
+
+			if q.Default != "" && q.Type != Password {
 				ui.Printf("Default: %v\n", q.Default)
 			}
 
-			answer = ui.ReadString(fmt.Sprintf("Enter %v: ", q.Name))
+			switch q.Type {
+			case Password:
+				answer = ui.ReadPassword(fmt.Sprintf("Enter %v: ", q.Name))
+			case Select:
+				answer = ui.ReadString(fmt.Sprintf("Select %v (number or value): ", q.Name))
+			case MultiSelect:
+				answer = ui.ReadString(fmt.Sprintf("Select %v (comma separated numbers or values): ", q.Name))
+			case Confirm:
+				answer = ui.ReadString(fmt.Sprintf("Confirm %v (yes/no): ", q.Name))
+			default:
+				answer = ui.ReadString(fmt.Sprintf("Enter %v: ", q.Name))
+			}
+
 			if answer == "" {
 				answer = q.Default
 			}
@@ -69,13 +106,136 @@ func Ask(ui console, questions []Question, vars map[string]string) (map[string]s
 			ui.Errorf("Invalid input! %v\n", q.ValidationMessage)
 		}
 
-		answers[q.Name] = answer
+		answers[q.Name] = Normalize(q, answer)
 	}
 
 	return answers, nil
 }
 
+// Answer questions without prompting, using values from vars and question defaults. Questions with neither
+// are reported as missing.
+func Answer(questions []Question, vars map[string]string) (map[string]string, error) {
+	var problems AnswersError
+
+	answers := vars
+
+	for _, q := range questions {
+		// skip question if condition is not met
+		if ok, err := When(q.When, answers); err != nil || !ok {
+			if err != nil {
+				return nil, fmt.Errorf("unable to evaluate condition of question %v: %v", q.Name, err)
+			}
+
+			continue
+		}
+
+		v, ok := vars[q.Name]
+		if !ok && q.Default == "" {
+			problems = append(problems, fmt.Sprintf("%v: answer is missing", q.Name))
+			continue
+		}
+
+		if !ok {
+			v = q.Default
+		}
+
+		valid, err := Valid(q, v)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case valid:
+			answers[q.Name] = Normalize(q, v)
+		case !ok:
+			problems = append(problems, fmt.Sprintf("%v: answer is missing", q.Name))
+		default:
+			problems = append(problems, fmt.Sprintf("%v: invalid answer %#v. %v", q.Name, v, q.ValidationMessage))
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, problems
+	}
+
+	return answers, nil
+}
+
+// Passwords filters questions of password type
+func Passwords(questions []Question) []Question {
+	var passwords []Question
+	for _, q := range questions {
+		if q.Type == Password {
+			passwords = append(passwords, q)
+		}
+	}
+
+	return passwords
+}
+
+// AnswersError lists answers which are missing or invalid
+type AnswersError []string
+
+func (e AnswersError) Error() string {
+	return fmt.Sprintf("%v answer(s) missing or invalid:\n  - %v", len(e), strings.Join(e, "\n  - "))
+}
+
+// Normalize answer: option numbers are replaced with option values, multiple options are joined with comma
+// and confirmation is turned into "true" or "false". Answers which can not be normalized are returned as is.
+func Normalize(q Question, val string) string {
+	switch q.Type {
+	case Select:
+		if option, ok := option(q, val); ok {
+			return option
+		}
+	case MultiSelect:
+		var selected []string
+		for _, v := range split(val) {
+			option, ok := option(q, v)
+			if !ok {
+				return val
+			}
+
+			selected = append(selected, option)
+		}
+
+		return strings.Join(selected, ",")
+	case Confirm:
+		switch strings.ToLower(strings.TrimSpace(val)) {
+		case "y", "yes", "true", "1":
+			return "true"
+		case "n", "no", "false", "0":
+			return "false"
+		}
+	}
+
+	return val
+}
+
 func Valid(q Question, val string) (bool, error) {
+	if (q.Type == Select || q.Type == MultiSelect) && len(q.Options) == 0 {
+		return false, fmt.Errorf("question %v of type %v has no options, questions file is invalid", q.Name, q.Type)
+	}
+
+	normalized := Normalize(q, val)
+
+	switch q.Type {
+	case Select:
+		if _, ok := option(q, normalized); !ok {
+			return false, nil
+		}
+	case MultiSelect:
+		for _, v := range split(normalized) {
+			if _, ok := option(q, v); !ok {
+				return false, nil
+			}
+		}
+	case Confirm:
+		if normalized != "true" && normalized != "false" {
+			return false, nil
+		}
+	}
+
 	if q.RegExp == "" {
 		return true, nil
 	}
@@ -85,5 +245,32 @@ func Valid(q Question, val string) (bool, error) {
 		return true, fmt.Errorf("unable to parse regexp for variable %v, questions file is invalid", q.Name)
 	}
 
-	return re.MatchString(val), nil
+	return re.MatchString(normalized), nil
+}
+
+// option of the question by its value or number (starting from 1)
+func option(q Question, val string) (string, bool) {
+	for _, option := range q.Options {
+		if option == val {
+			return option, true
+		}
+	}
+
+	if n, err := strconv.Atoi(val); err == nil && n > 0 && n <= len(q.Options) {
+		return q.Options[n-1], true
+	}
+
+	return "", false
+}
+
+// split comma separated list, ignoring empty items
+func split(val string) []string {
+	var items []string
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
 }
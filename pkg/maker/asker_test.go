@@ -0,0 +1,144 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package maker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnswer(t *testing.T) {
+	questions := []Question{
+		{Name: "name", RegExp: "^[a-z]+$"},
+		{Name: "team", Default: "core"},
+		{Name: "owner"},
+	}
+
+	got, err := Answer(questions, map[string]string{"name": "awesome", "owner": ""})
+	if err != nil {
+		t.Fatalf("Answer should not return an error, but it returned %v", err)
+	}
+
+	want := map[string]string{"name": "awesome", "team": "core", "owner": ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Answers do not match: got %#v, want %#v", got, want)
+	}
+}
+
+func TestAnswer_ListsAllProblems(t *testing.T) {
+	questions := []Question{
+		{Name: "name", RegExp: "^[a-z]+$"},
+		{Name: "team", RegExp: "^[a-z]+$", ValidationMessage: "Must be lowercase"},
+		{Name: "owner", RegExp: "^[a-z]+$", Default: "nobody"},
+	}
+
+	_, err := Answer(questions, map[string]string{"team": "Core"})
+
+	problems, ok := err.(AnswersError)
+	if !ok {
+		t.Fatalf("Answer should return AnswersError, but it returned %#v", err)
+	}
+
+	want := AnswersError{
+		`name: answer is missing`,
+		`team: invalid answer "Core". Must be lowercase`,
+	}
+
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("Problems do not match: got %#v, want %#v", problems, want)
+	}
+}
+
+func TestAnswer_MissingWithoutDefault(t *testing.T) {
+	questions := []Question{
+		{Name: "owner"},
+		{Name: "private", Type: Confirm},
+	}
+
+	_, err := Answer(questions, map[string]string{})
+
+	want := AnswersError{
+		`owner: answer is missing`,
+		`private: answer is missing`,
+	}
+
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("Error does not match: got %#v, want %#v", err, want)
+	}
+}
+
+func TestValid(t *testing.T) {
+	options := []string{"mysql", "postgres", "redis"}
+
+	tests := []struct {
+		question   Question
+		input      string
+		valid      bool
+		normalized string
+	}{
+		{question: Question{Type: Input, RegExp: "^[a-z]+$"}, input: "abc", valid: true, normalized: "abc"},
+		{question: Question{Type: Input, RegExp: "^[a-z]+$"}, input: "ABC", valid: false, normalized: "ABC"},
+		{question: Question{Type: Password}, input: "s3cr3t", valid: true, normalized: "s3cr3t"},
+		{question: Question{Type: Select, Options: options}, input: "postgres", valid: true, normalized: "postgres"},
+		{question: Question{Type: Select, Options: options}, input: "2", valid: true, normalized: "postgres"},
+		{question: Question{Type: Select, Options: options}, input: "4", valid: false, normalized: "4"},
+		{question: Question{Type: Select, Options: options}, input: "mongo", valid: false, normalized: "mongo"},
+		{question: Question{Type: MultiSelect, Options: options}, input: "mysql, 3", valid: true, normalized: "mysql,redis"},
+		{question: Question{Type: MultiSelect, Options: options}, input: "", valid: true, normalized: ""},
+		{question: Question{Type: MultiSelect, Options: options}, input: "mysql,mongo", valid: false, normalized: "mysql,mongo"},
+		{question: Question{Type: Confirm}, input: "yes", valid: true, normalized: "true"},
+		{question: Question{Type: Confirm}, input: "N", valid: true, normalized: "false"},
+		{question: Question{Type: Confirm}, input: "1", valid: true, normalized: "true"},
+		{question: Question{Type: Confirm}, input: "maybe", valid: false, normalized: "maybe"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.question.Type+"/"+test.input, func(t *testing.T) {
+			valid, err := Valid(test.question, test.input)
+			if err != nil {
+				t.Fatalf("Valid should not return an error, but it returned %v", err)
+			}
+
+			if valid != test.valid {
+				t.Errorf("Validation result does not match: got %v, want %v", valid, test.valid)
+			}
+
+			if got := Normalize(test.question, test.input); got != test.normalized {
+				t.Errorf("Normalized value does not match: got %#v, want %#v", got, test.normalized)
+			}
+		})
+	}
+}
+
+func TestValid_SelectWithoutOptions(t *testing.T) {
+	if _, err := Valid(Question{Name: "db", Type: Select}, "mysql"); err == nil {
+		t.Errorf("Valid should return an error when select question has no options")
+	}
+}
+
+func TestAnswer_SkipsQuestionsByCondition(t *testing.T) {
+	questions := []Question{
+		{Name: "use_database", Type: Confirm},
+		{Name: "database", Type: Select, Options: []string{"mysql", "postgres"}, When: "use_database"},
+		{Name: "database_name", When: `database == "postgres"`},
+	}
+
+	got, err := Answer(questions, map[string]string{"use_database": "no"})
+	if err != nil {
+		t.Fatalf("Answer should not return an error, but it returned %v", err)
+	}
+
+	want := map[string]string{"use_database": "false"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Answers do not match: got %#v, want %#v", got, want)
+	}
+}
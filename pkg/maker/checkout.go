@@ -12,23 +12,60 @@ written permission of Adobe.
 package maker
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 )
 
-// Checkout template repository
-func Checkout(destination, template, branch string) error {
+// Checkout template repository and return revision (commit SHA) which has been checked out. When revision is empty,
+// the latest commit of the branch is used.
+func Checkout(destination, template, branch, revision string) (string, error) {
 	if err := run("git", "clone", "--depth=1", "--branch="+branch, template, destination); err != nil {
-		return fmt.Errorf("unable to clone template repository %#v into destination folder: %v", template, err)
+		return "", fmt.Errorf("unable to clone template repository %#v into destination folder: %v", template, err)
+	}
+
+	if revision != "" {
+		// not every server allows to fetch commit by SHA, so fall back to fetching full history of the branch
+		if err := git(destination, "fetch", "--depth=1", "origin", revision); err != nil {
+			if err := git(destination, "fetch", "--unshallow", "origin"); err != nil {
+				return "", fmt.Errorf("unable to fetch template revision %v: %v", revision, err)
+			}
+		}
+
+		if err := git(destination, "checkout", "--quiet", revision); err != nil {
+			return "", fmt.Errorf("unable to checkout template revision %v: %v", revision, err)
+		}
+	}
+
+	out := bytes.NewBuffer(nil)
+
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = destination
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("unable to resolve template revision: %v", err)
 	}
 
 	if err := os.RemoveAll(filepath.Join(destination, ".git")); err != nil {
-		return fmt.Errorf("unable to remote .git folder of template repository: %v", err)
+		return "", fmt.Errorf("unable to remote .git folder of template repository: %v", err)
 	}
 
-	return nil
+	return strings.TrimSpace(out.String()), nil
+}
+
+// git command executed in given directory
+func git(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stderr = os.Stderr
+	cmd.Stdout = os.Stdout
+
+	return cmd.Run()
 }
 
 // run a cli command
@@ -18,20 +18,24 @@ import (
 type Config struct {
 	Questions []Question
 	Tasks     []Task
+	Ignore    []string `yaml:"ignore"`
 }
 
 type Question struct {
-	Message           string `yaml:"message"`
-	Name              string `yaml:"name"`
-	Type              string `yaml:"type"`
-	Default           string `yaml:"default"`
-	RegExp            string `yaml:"regexp"`
-	ValidationMessage string `yaml:"validation_msg"`
-	HelpMessage       string `yaml:"help_msg"`
+	Message           string   `yaml:"message"`
+	Name              string   `yaml:"name"`
+	Type              string   `yaml:"type"`
+	Default           string   `yaml:"default"`
+	RegExp            string   `yaml:"regexp"`
+	ValidationMessage string   `yaml:"validation_msg"`
+	HelpMessage       string   `yaml:"help_msg"`
+	Options           []string `yaml:"options"`
+	When              string   `yaml:"when"`
 }
 
 type Task struct {
 	Command StringOrSlice
+	When    string `yaml:"when"`
 }
 
 type StringOrSlice []string
@@ -0,0 +1,236 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package maker
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+)
+
+// Change statuses reported by Merge
+const (
+	Added     = "added"
+	Updated   = "updated"
+	Merged    = "merged"
+	Deleted   = "deleted"
+	Conflict  = "conflict"
+	Untouched = "untouched"
+)
+
+// Change made to a project file by Merge
+type Change struct {
+	Path    string
+	Status  string
+	Message string
+}
+
+// Merge changes between two renders of the template (base and next) into the project. Files changed both in
+// the template and in the project are merged using three-way merge, conflicts are left in the file as markers.
+func Merge(base, next, project string) ([]Change, error) {
+	baseFiles, err := files(base)
+	if err != nil {
+		return nil, err
+	}
+
+	nextFiles, err := files(next)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]bool)
+	for path := range baseFiles {
+		paths[path] = true
+	}
+
+	for path := range nextFiles {
+		paths[path] = true
+	}
+
+	sorted := make([]string, 0, len(paths))
+	for path := range paths {
+		sorted = append(sorted, path)
+	}
+
+	sort.Strings(sorted)
+
+	var changes []Change
+
+	for _, path := range sorted {
+		change, err := merge(path, base, next, project, baseFiles[path], nextFiles[path])
+		if err != nil {
+			return changes, fmt.Errorf("unable to merge %#v: %v", path, err)
+		}
+
+		if change.Status != "" {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, nil
+}
+
+// merge a single file, inBase and inNext tell whether file exists in the corresponding render
+func merge(path, base, next, project string, inBase, inNext bool) (Change, error) {
+	change := Change{Path: path}
+
+	baseFile, nextFile, ourFile := filepath.Join(base, path), filepath.Join(next, path), filepath.Join(project, path)
+
+	ours, err := ioutil.ReadFile(ourFile)
+	inProject := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return change, err
+	}
+
+	var theirs, original []byte
+
+	if inNext {
+		if theirs, err = ioutil.ReadFile(nextFile); err != nil {
+			return change, err
+		}
+	}
+
+	if inBase {
+		if original, err = ioutil.ReadFile(baseFile); err != nil {
+			return change, err
+		}
+	}
+
+	switch {
+	case inBase && inNext && bytes.Equal(original, theirs):
+		// file has not been changed in the template
+		return change, nil
+
+	case !inNext:
+		// file has been removed from the template
+		if !inProject {
+			return change, nil
+		}
+
+		if !bytes.Equal(ours, original) {
+			change.Status, change.Message = Untouched, "removed from template, but modified in project"
+			return change, nil
+		}
+
+		change.Status = Deleted
+		return change, os.Remove(ourFile)
+
+	case !inProject && inBase:
+		// file has been removed from the project
+		change.Status, change.Message = Untouched, "changed in template, but removed from project"
+		return change, nil
+
+	case !inProject:
+		// file has been added to the template
+		change.Status = Added
+		return change, copyFile(nextFile, ourFile)
+
+	case bytes.Equal(ours, theirs):
+		// project already has the change
+		return change, nil
+
+	case inBase && bytes.Equal(ours, original):
+		// file has not been changed in the project
+		change.Status = Updated
+		return change, copyFile(nextFile, ourFile)
+	}
+
+	// file has been changed both in the template and in the project
+	if !inBase {
+		empty, err := ioutil.TempFile("", "go-starter-merge")
+		if err != nil {
+			return change, err
+		}
+
+		defer os.Remove(empty.Name())
+		_ = empty.Close()
+
+		baseFile = empty.Name()
+	}
+
+	out := bytes.NewBuffer(nil)
+
+	cmd := exec.Command("git", "merge-file", "-p", "-L", "project", "-L", "template (old)", "-L", "template (new)", ourFile, baseFile, nextFile)
+	cmd.Stdout = out
+
+	err = cmd.Run()
+	if exit, ok := err.(*exec.ExitError); ok {
+		// merge-file exits with number of conflicts, or negative value if merge is not possible (eq. binary file)
+		if code := exit.ExitCode(); code < 0 || code > 127 {
+			change.Status, change.Message = Untouched, "changed both in template and in project, unable to merge"
+			return change, nil
+		}
+
+		change.Status, change.Message = Conflict, "changed both in template and in project, resolve conflict markers"
+	} else if err != nil {
+		return change, err
+	} else {
+		change.Status = Merged
+	}
+
+	return change, ioutil.WriteFile(ourFile, out.Bytes(), 0666)
+}
+
+// files in the directory, relative to it; .git folder and recorded answers are excluded
+func files(dir string) (map[string]bool, error) {
+	list := make(map[string]bool)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() && rel == ".git" {
+			return filepath.SkipDir
+		}
+
+		if !info.IsDir() && rel != RecordFile {
+			list[rel] = true
+		}
+
+		return nil
+	})
+
+	return list, err
+}
+
+// copyFile with its permissions, creating parent directories when needed
+func copyFile(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0777); err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(dst, data, info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	return os.Chmod(dst, info.Mode().Perm())
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package maker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	dir, err := ioutil.TempDir("", "merge")
+	if err != nil {
+		t.Fatalf("Unable to create temporary dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	base, next, project := filepath.Join(dir, "base"), filepath.Join(dir, "next"), filepath.Join(dir, "project")
+
+	write := func(root, name, content string) {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatalf("Unable to create test path: %v", err)
+		}
+
+		if err := ioutil.WriteFile(path, []byte(content), 0666); err != nil {
+			t.Fatalf("Unable to create test file: %v", err)
+		}
+	}
+
+	// unchanged in template, changed in project
+	write(base, "same.txt", "a\n")
+	write(next, "same.txt", "a\n")
+	write(project, "same.txt", "local\n")
+
+	// changed in template only
+	write(base, "updated.txt", "a\n")
+	write(next, "updated.txt", "b\n")
+	write(project, "updated.txt", "a\n")
+
+	// changed in both without overlapping
+	write(base, "merged.txt", "1\n2\n3\n4\n5\n")
+	write(next, "merged.txt", "1\n2\n3\n4\nfive\n")
+	write(project, "merged.txt", "one\n2\n3\n4\n5\n")
+
+	// changed in both with overlapping
+	write(base, "conflict.txt", "a\n")
+	write(next, "conflict.txt", "b\n")
+	write(project, "conflict.txt", "c\n")
+
+	// added to template
+	write(next, "nested/added.txt", "new\n")
+
+	// removed from template
+	write(base, "deleted.txt", "a\n")
+	write(project, "deleted.txt", "a\n")
+
+	// removed from template, but modified in project
+	write(base, "modified.txt", "a\n")
+	write(project, "modified.txt", "b\n")
+
+	// recorded answers are never merged
+	write(base, RecordFile, "a\n")
+	write(next, RecordFile, "b\n")
+	write(project, RecordFile, "c\n")
+
+	changes, err := Merge(base, next, project)
+	if err != nil {
+		t.Fatalf("Merge should not return an error, but it returned %v", err)
+	}
+
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.Status+" "+c.Path)
+	}
+
+	want := []string{
+		"conflict conflict.txt",
+		"deleted deleted.txt",
+		"merged merged.txt",
+		"untouched modified.txt",
+		"added nested/added.txt",
+		"updated updated.txt",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Changes do not match: got %#v, want %#v", got, want)
+	}
+
+	files := map[string]string{
+		"same.txt":         "local\n",
+		"updated.txt":      "b\n",
+		"merged.txt":       "one\n2\n3\n4\nfive\n",
+		"nested/added.txt": "new\n",
+		"modified.txt":     "b\n",
+		RecordFile:         "c\n",
+	}
+
+	for name, want := range files {
+		data, err := ioutil.ReadFile(filepath.Join(project, name))
+		if err != nil {
+			t.Errorf("Unable to read %v: %v", name, err)
+			continue
+		}
+
+		if got := string(data); got != want {
+			t.Errorf("File %v does not match: got %#v, want %#v", name, got, want)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(project, "deleted.txt")); !os.IsNotExist(err) {
+		t.Errorf("File deleted.txt should be removed")
+	}
+
+	data, _ := ioutil.ReadFile(filepath.Join(project, "conflict.txt"))
+	if !strings.Contains(string(data), "<<<<<<< project") || !strings.Contains(string(data), ">>>>>>> template (new)") {
+		t.Errorf("File conflict.txt should contain conflict markers, got %#v", string(data))
+	}
+}
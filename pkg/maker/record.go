@@ -0,0 +1,88 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package maker
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+)
+
+// RecordFile is written into generated project and keeps answers used to generate it
+const RecordFile = ".starter-answers.yml"
+
+// service variables are set by go-starter itself and not recorded as answers
+var service = map[string]bool{
+	"template_url":      true,
+	"template_branch":   true,
+	"template_revision": true,
+	"destination":       true,
+	"upgrade":           true,
+}
+
+// Record of the template and answers used to generate a project
+type Record struct {
+	TemplateURL      string            `yaml:"template_url"`
+	TemplateBranch   string            `yaml:"template_branch"`
+	TemplateRevision string            `yaml:"template_revision"`
+	Answers          map[string]string `yaml:"answers"`
+}
+
+// NewRecord from variables, template details are taken from service variables. Answers to password questions
+// are not recorded, they have to be entered again when project is replayed or upgraded.
+func NewRecord(questions []Question, vars map[string]string) Record {
+	secret := make(map[string]bool)
+	for _, q := range Passwords(questions) {
+		secret[q.Name] = true
+	}
+
+	r := Record{
+		TemplateURL:      vars["template_url"],
+		TemplateBranch:   vars["template_branch"],
+		TemplateRevision: vars["template_revision"],
+		Answers:          make(map[string]string),
+	}
+
+	for k, v := range vars {
+		if !service[k] && !secret[k] {
+			r.Answers[k] = v
+		}
+	}
+
+	return r
+}
+
+// SaveRecord into a file
+func SaveRecord(file string, r Record) error {
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	header := "# This file is generated by go-starter, it is used to replay or upgrade the project from the template\n"
+
+	return ioutil.WriteFile(file, append([]byte(header), data...), 0666)
+}
+
+// LoadRecord from a file
+func LoadRecord(file string) (r Record, err error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return
+	}
+
+	if err = yaml.Unmarshal(data, &r); err != nil {
+		err = fmt.Errorf("unable to parse record file %#v: %v", file, err)
+	}
+
+	return
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package maker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "record")
+	if err != nil {
+		t.Fatalf("Unable to create temporary dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	questions := []Question{
+		{Name: "application_name"},
+		{Name: "api_token", Type: Password},
+	}
+
+	record := NewRecord(questions, map[string]string{
+		"template_url":      "https://github.com/adobe/go-scaffolding",
+		"template_branch":   "master",
+		"template_revision": "0123456789abcdef",
+		"destination":       "awesome-project",
+		"application_name":  "awesome-project",
+		"api_token":         "s3cr3t-t0k3n",
+	})
+
+	want := Record{
+		TemplateURL:      "https://github.com/adobe/go-scaffolding",
+		TemplateBranch:   "master",
+		TemplateRevision: "0123456789abcdef",
+		Answers:          map[string]string{"application_name": "awesome-project"},
+	}
+
+	if !reflect.DeepEqual(record, want) {
+		t.Fatalf("Record does not match: got %#v, want %#v", record, want)
+	}
+
+	file := filepath.Join(dir, RecordFile)
+
+	if err := SaveRecord(file, record); err != nil {
+		t.Fatalf("SaveRecord should not return an error, but it returned %v", err)
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Unable to read record file: %v", err)
+	}
+
+	if strings.Contains(string(data), "s3cr3t-t0k3n") || strings.Contains(string(data), "api_token") {
+		t.Errorf("Record file should not contain password answers, but it does:\n%s", data)
+	}
+
+	got, err := LoadRecord(file)
+	if err != nil {
+		t.Fatalf("LoadRecord should not return an error, but it returned %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Loaded record does not match: got %#v, want %#v", got, want)
+	}
+}
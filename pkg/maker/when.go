@@ -0,0 +1,358 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package maker
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// When evaluates condition expression against variables. Empty expression is always true.
+//
+// Expression supports string literals ("value" or 'value'), variable names, list literals (["a", "b"]),
+// comparison (==, !=), membership tests (in, not in) and boolean operators (and, or, not, &&, ||, !) with
+// parentheses. Variables which are not defined are empty. Variable alone is true unless it's empty
+// or one of "false", "no", "n", "off" or "0". Membership test against a variable treats its value as
+// a comma separated list, so it works with multiselect questions.
+//
+// Example:
+//
+//	use_database and database in ["mysql", "postgres"]
+//	"grpc" in features or not (ci == "drone")
+func When(expr string, vars map[string]string) (bool, error) {
+	if strings.TrimSpace(expr) == "" {
+		return true, nil
+	}
+
+	tokens, err := lex(expr)
+	if err != nil {
+		return false, fmt.Errorf("invalid expression %#v: %v", expr, err)
+	}
+
+	p := &parser{tokens: tokens, vars: vars}
+
+	v, err := p.or()
+	if err == nil && p.peek().kind != tokenEnd {
+		err = fmt.Errorf("unexpected %#v", p.peek().text)
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("invalid expression %#v: %v", expr, err)
+	}
+
+	return v.truthy(), nil
+}
+
+const (
+	tokenEnd = iota
+	tokenString
+	tokenIdent
+	tokenOperator
+)
+
+type token struct {
+	kind int
+	text string
+}
+
+// lex expression into list of tokens
+func lex(expr string) ([]token, error) {
+	var tokens []token
+
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+
+			tokens = append(tokens, token{kind: tokenString, text: string(runes[i+1 : end])})
+			i = end + 1
+
+		case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			end := i
+			for end < len(runes) && (runes[end] == '_' || unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end])) {
+				end++
+			}
+
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[i:end])})
+			i = end
+
+		default:
+			op := string(r)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "==", "!=", "&&", "||":
+					op = two
+				}
+			}
+
+			switch op {
+			case "==", "!=", "&&", "||", "!", "(", ")", "[", "]", ",":
+			default:
+				return nil, fmt.Errorf("unexpected character %#v", op)
+			}
+
+			tokens = append(tokens, token{kind: tokenOperator, text: op})
+			i += len([]rune(op))
+		}
+	}
+
+	return append(tokens, token{kind: tokenEnd}), nil
+}
+
+// value of expression, either a string or a list of strings
+type value struct {
+	str    string
+	list   []string
+	isList bool
+}
+
+func boolean(b bool) value {
+	if b {
+		return value{str: "true"}
+	}
+
+	return value{str: "false"}
+}
+
+func (v value) truthy() bool {
+	if v.isList {
+		return len(v.list) > 0
+	}
+
+	switch strings.ToLower(v.str) {
+	case "", "false", "no", "n", "off", "0":
+		return false
+	}
+
+	return true
+}
+
+// items of the value, string values are treated as comma separated list
+func (v value) items() []string {
+	if v.isList {
+		return v.list
+	}
+
+	return split(v.str)
+}
+
+// parser is a recursive descent parser which evaluates expression while parsing it
+type parser struct {
+	tokens []token
+	pos    int
+	vars   map[string]string
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEnd {
+		p.pos++
+	}
+
+	return t
+}
+
+// accept next token if it's one of the keywords or operators
+func (p *parser) accept(texts ...string) bool {
+	t := p.peek()
+	if t.kind != tokenIdent && t.kind != tokenOperator {
+		return false
+	}
+
+	for _, text := range texts {
+		if t.text == text {
+			p.pos++
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		return fmt.Errorf("expected %#v, got %#v", text, p.peek().text)
+	}
+
+	return nil
+}
+
+// or := and (("or" | "||") and)*
+func (p *parser) or() (value, error) {
+	left, err := p.and()
+	if err != nil {
+		return left, err
+	}
+
+	for p.accept("or", "||") {
+		right, err := p.and()
+		if err != nil {
+			return right, err
+		}
+
+		left = boolean(left.truthy() || right.truthy())
+	}
+
+	return left, nil
+}
+
+// and := not (("and" | "&&") not)*
+func (p *parser) and() (value, error) {
+	left, err := p.not()
+	if err != nil {
+		return left, err
+	}
+
+	for p.accept("and", "&&") {
+		right, err := p.not()
+		if err != nil {
+			return right, err
+		}
+
+		left = boolean(left.truthy() && right.truthy())
+	}
+
+	return left, nil
+}
+
+// not := ("not" | "!") not | compare
+func (p *parser) not() (value, error) {
+	if p.accept("not", "!") {
+		v, err := p.not()
+		return boolean(!v.truthy()), err
+	}
+
+	return p.compare()
+}
+
+// compare := operand (("==" | "!=" | "in" | "not" "in") operand)?
+func (p *parser) compare() (value, error) {
+	left, err := p.operand()
+	if err != nil {
+		return left, err
+	}
+
+	negate := false
+
+	switch {
+	case p.accept("=="):
+	case p.accept("!="):
+		negate = true
+	case p.accept("in"):
+		right, err := p.operand()
+		return boolean(contains(right.items(), left.str)), err
+	case p.peek().kind == tokenIdent && p.peek().text == "not" && p.tokens[p.pos+1].text == "in":
+		p.pos += 2
+		right, err := p.operand()
+		return boolean(!contains(right.items(), left.str)), err
+	default:
+		return left, nil
+	}
+
+	right, err := p.operand()
+	if err != nil {
+		return right, err
+	}
+
+	equal := left.str == right.str
+	if left.isList || right.isList {
+		equal = strings.Join(left.items(), ",") == strings.Join(right.items(), ",")
+	}
+
+	return boolean(equal != negate), nil
+}
+
+// operand := string | variable | list | "(" or ")"
+func (p *parser) operand() (value, error) {
+	t := p.next()
+
+	switch {
+	case t.kind == tokenString:
+		return value{str: t.text}, nil
+
+	case t.kind == tokenIdent:
+		switch t.text {
+		case "and", "or", "not", "in":
+			return value{}, fmt.Errorf("unexpected %#v", t.text)
+		case "true", "false":
+			return value{str: t.text}, nil
+		}
+
+		if unicode.IsDigit([]rune(t.text)[0]) {
+			return value{str: t.text}, nil
+		}
+
+		return value{str: p.vars[t.text]}, nil
+
+	case t.text == "(":
+		v, err := p.or()
+		if err != nil {
+			return v, err
+		}
+
+		return v, p.expect(")")
+
+	case t.text == "[":
+		list := value{isList: true}
+
+		for !p.accept("]") {
+			if len(list.list) > 0 {
+				if err := p.expect(","); err != nil {
+					return list, err
+				}
+			}
+
+			item, err := p.operand()
+			if err != nil {
+				return list, err
+			}
+
+			list.list = append(list.list, item.str)
+		}
+
+		return list, nil
+
+	case t.kind == tokenEnd:
+		return value{}, fmt.Errorf("unexpected end of expression")
+	}
+
+	return value{}, fmt.Errorf("unexpected %#v", t.text)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
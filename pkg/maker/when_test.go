@@ -0,0 +1,85 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package maker
+
+import "testing"
+
+func TestWhen(t *testing.T) {
+	vars := map[string]string{
+		"use_database": "true",
+		"use_ci":       "false",
+		"database":     "postgres",
+		"features":     "metrics,grpc",
+		"empty":        "",
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{expr: ``, want: true},
+		{expr: `use_database`, want: true},
+		{expr: `use_ci`, want: false},
+		{expr: `empty`, want: false},
+		{expr: `undefined`, want: false},
+		{expr: `not use_ci`, want: true},
+		{expr: `!use_database`, want: false},
+		{expr: `database == "postgres"`, want: true},
+		{expr: `database == 'mysql'`, want: false},
+		{expr: `database != "mysql"`, want: true},
+		{expr: `database in ["mysql", "postgres"]`, want: true},
+		{expr: `database not in ["mysql", "postgres"]`, want: false},
+		{expr: `"grpc" in features`, want: true},
+		{expr: `"tracing" in features`, want: false},
+		{expr: `"tracing" not in features`, want: true},
+		{expr: `use_database and database == "postgres"`, want: true},
+		{expr: `use_database && use_ci`, want: false},
+		{expr: `use_ci or "grpc" in features`, want: true},
+		{expr: `use_ci || empty`, want: false},
+		{expr: `not (use_ci or empty) and use_database`, want: true},
+		{expr: `use_ci == false`, want: true},
+		{expr: `[] == empty`, want: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			got, err := When(test.expr, vars)
+			if err != nil {
+				t.Fatalf("When should not return an error, but it returned %v", err)
+			}
+
+			if got != test.want {
+				t.Errorf("Result does not match: got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWhen_InvalidExpression(t *testing.T) {
+	tests := []string{
+		`database ==`,
+		`database = "postgres"`,
+		`"unterminated`,
+		`(use_ci`,
+		`database in ["mysql" "postgres"]`,
+		`use_ci use_database`,
+		`and`,
+	}
+
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := When(expr, nil); err == nil {
+				t.Errorf("When should return an error for invalid expression")
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package scm
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Bitbucket Server provider, owner is a project key (personal projects are referenced as ~username). Repositories
+// are referenced by slug which Bitbucket derives from the name (lower-cased, with spaces and special characters
+// replaced). Secrets are not supported, code owner reviews, dismissal of stale reviews and enforcement for admins
+// are ignored.
+type Bitbucket struct {
+	client *client
+	login  string
+}
+
+// NewBitbucket builds provider for Bitbucket Server at a given URL (including context path) authenticated with HTTP
+// access token
+func NewBitbucket(u *url.URL, token string) *Bitbucket {
+	return &Bitbucket{client: newClient(u.String()+"/rest", "Bearer "+token)}
+}
+
+type bitbucketLink struct {
+	Href string `json:"href"`
+	Name string `json:"name"`
+}
+
+type bitbucketRepository struct {
+	Slug  string `json:"slug"`
+	Links struct {
+		Self  []bitbucketLink `json:"self"`
+		Clone []bitbucketLink `json:"clone"`
+	} `json:"links"`
+}
+
+// bitbucketPermissions maps permissions to Bitbucket Server ones
+var bitbucketPermissions = map[Permission]string{Read: "REPO_READ", Write: "REPO_WRITE", Admin: "REPO_ADMIN"}
+
+// User returns username of authenticated user, Bitbucket Server returns it in X-AUSERNAME header
+func (b *Bitbucket) User() (string, error) {
+	if b.login != "" {
+		return b.login, nil
+	}
+
+	header, err := b.client.request("GET", "/api/1.0/profile/recent/repos?limit=1", nil, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if b.login = header.Get("X-AUSERNAME"); b.login == "" {
+		return "", fmt.Errorf("unable to detect user of the token")
+	}
+
+	return b.login, nil
+}
+
+// CreateRepository in a project
+func (b *Bitbucket) CreateRepository(owner, name string, private bool) (*Repository, error) {
+	repo := new(bitbucketRepository)
+
+	err := b.client.do("POST", "/api/1.0/projects/"+url.PathEscape(owner)+"/repos", map[string]interface{}{
+		"name":   name,
+		"scmId":  "git",
+		"public": !private,
+	}, repo)
+
+	if isStatus(err, http.StatusConflict) {
+		return nil, ErrExists
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Repository{Owner: owner, Name: name, Slug: repo.Slug}
+
+	if len(repo.Links.Self) > 0 {
+		r.WebURL = repo.Links.Self[0].Href
+	}
+
+	for _, l := range repo.Links.Clone {
+		if l.Name == "http" || l.Name == "https" {
+			r.CloneURL = l.Href
+		}
+	}
+
+	return r, nil
+}
+
+// AddCollaborator grants user permissions to the repository
+func (b *Bitbucket) AddCollaborator(owner, name, user string, perm Permission) error {
+	p, ok := bitbucketPermissions[perm]
+	if !ok {
+		return fmt.Errorf("unknown permission %#v", perm)
+	}
+
+	query := url.Values{"name": {user}, "permission": {p}}
+	return b.client.do("PUT", "/api/1.0"+b.repo(owner, name)+"/permissions/users?"+query.Encode(), nil, nil)
+}
+
+// AddDeployKey to the repository, title is used as a label of the key
+func (b *Bitbucket) AddDeployKey(owner, name, title, key string, readOnly bool) error {
+	perm := "REPO_WRITE"
+	if readOnly {
+		perm = "REPO_READ"
+	}
+
+	return b.client.do("POST", "/keys/1.0"+b.repo(owner, name)+"/ssh", map[string]interface{}{
+		"key":        map[string]string{"text": key, "label": title},
+		"permission": perm,
+	}, nil)
+}
+
+// SetSecret is not supported by Bitbucket Server
+func (b *Bitbucket) SetSecret(owner, name, key, value string) error {
+	return ErrUnsupported
+}
+
+// ProtectBranch of the repository: deletion and history rewrites are prevented, required reviews and status checks
+// are set as merge checks of pull requests, push restrictions allow only given users and groups to push
+func (b *Bitbucket) ProtectBranch(owner, name, branch string, p Protection) error {
+	repo := b.repo(owner, name)
+
+	if p.RequiredReviews > 0 || len(p.RequiredStatusChecks) > 0 {
+		err := b.client.do("POST", "/api/1.0"+repo+"/settings/pull-requests", map[string]int{
+			"requiredApprovers":        p.RequiredReviews,
+			"requiredSuccessfulBuilds": len(p.RequiredStatusChecks),
+		}, nil)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	restrictions := []map[string]interface{}{
+		{"type": "no-deletes"},
+		{"type": "fast-forward-only"},
+	}
+
+	if p.RequiredReviews > 0 {
+		restrictions = append(restrictions, map[string]interface{}{"type": "pull-request-only"})
+	}
+
+	if len(p.RestrictPushUsers) > 0 || len(p.RestrictPushTeams) > 0 {
+		restrictions = append(restrictions, map[string]interface{}{
+			"type":   "read-only",
+			"users":  append([]string{}, p.RestrictPushUsers...),
+			"groups": append([]string{}, p.RestrictPushTeams...),
+		})
+	}
+
+	for _, r := range restrictions {
+		r["matcher"] = map[string]interface{}{
+			"id":        "refs/heads/" + branch,
+			"displayId": branch,
+			"type":      map[string]string{"id": "BRANCH", "name": "Branch"},
+			"active":    true,
+		}
+
+		if err := b.client.do("POST", "/branch-permissions/2.0"+repo+"/restrictions", r, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// repo path by project key and repository slug
+func (b *Bitbucket) repo(owner, slug string) string {
+	return fmt.Sprintf("/projects/%v/repos/%v", url.PathEscape(owner), url.PathEscape(slug))
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package scm
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestBitbucket(t *testing.T) {
+	fake, server, u := newFakeServer(t, "Bearer secret", map[string]response{
+		"GET /rest/api/1.0/profile/recent/repos?limit=1": {body: `{"values":[]}`, header: map[string]string{"X-AUSERNAME": "octocat"}},
+		"POST /rest/api/1.0/projects/ADOBE/repos": {status: http.StatusCreated, body: `{"slug":"awesome-project","links":{
+			"self":[{"href":"https://bitbucket.example.com/projects/ADOBE/repos/awesome-project/browse"}],
+			"clone":[{"href":"ssh://git@bitbucket.example.com:7999/adobe/awesome-project.git","name":"ssh"},{"href":"https://bitbucket.example.com/scm/adobe/awesome-project.git","name":"http"}]}}`},
+		"POST /rest/api/1.0/projects/~octocat/repos":                                                                {status: http.StatusConflict, body: `{"errors":[{"message":"This repository name is already taken."}]}`},
+		"PUT /rest/api/1.0/projects/ADOBE/repos/awesome-project/permissions/users?name=hubot&permission=REPO_ADMIN": {status: http.StatusNoContent},
+		"POST /rest/keys/1.0/projects/ADOBE/repos/awesome-project/ssh":                                              {status: http.StatusCreated, body: `{}`},
+		"POST /rest/api/1.0/projects/ADOBE/repos/awesome-project/settings/pull-requests":                            {body: `{}`},
+		"POST /rest/branch-permissions/2.0/projects/ADOBE/repos/awesome-project/restrictions":                       {body: `{}`},
+	})
+
+	defer server.Close()
+
+	b := NewBitbucket(u, "secret")
+
+	user, err := b.User()
+	if err != nil || user != "octocat" {
+		t.Errorf("User() does not match: got %#v, %v", user, err)
+	}
+
+	repo, err := b.CreateRepository("ADOBE", "Awesome Project", true)
+	if err != nil {
+		t.Fatalf("CreateRepository() failed: %v", err)
+	}
+
+	want := &Repository{Owner: "ADOBE", Name: "Awesome Project", Slug: "awesome-project", WebURL: "https://bitbucket.example.com/projects/ADOBE/repos/awesome-project/browse", CloneURL: "https://bitbucket.example.com/scm/adobe/awesome-project.git"}
+	if !reflect.DeepEqual(repo, want) {
+		t.Errorf("Repository does not match: got %#v, want %#v", repo, want)
+	}
+
+	if _, err := b.CreateRepository("~octocat", "awesome", true); err != ErrExists {
+		t.Errorf("CreateRepository() error does not match: got %v, want %v", err, ErrExists)
+	}
+
+	if err := b.AddCollaborator("ADOBE", repo.Slug, "hubot", Admin); err != nil {
+		t.Fatalf("AddCollaborator() failed: %v", err)
+	}
+
+	if err := b.AddDeployKey("ADOBE", repo.Slug, "Deploy Key", "ssh-rsa AAAA", false); err != nil {
+		t.Fatalf("AddDeployKey() failed: %v", err)
+	}
+
+	if err := b.SetSecret("ADOBE", repo.Slug, "TOKEN", "value"); err != ErrUnsupported {
+		t.Errorf("SetSecret() error does not match: got %v, want %v", err, ErrUnsupported)
+	}
+
+	p := Protection{RequiredReviews: 1, RequiredStatusChecks: []string{"ci"}, RestrictPushTeams: []string{"developers"}}
+	if err := b.ProtectBranch("ADOBE", repo.Slug, "master", p); err != nil {
+		t.Fatalf("ProtectBranch() failed: %v", err)
+	}
+
+	key := map[string]interface{}{"key": map[string]interface{}{"text": "ssh-rsa AAAA", "label": "Deploy Key"}, "permission": "REPO_WRITE"}
+	if got := fake.requests["POST /rest/keys/1.0/projects/ADOBE/repos/awesome-project/ssh"]; !reflect.DeepEqual(got, key) {
+		t.Errorf("Deploy key does not match: got %#v, want %#v", got, key)
+	}
+
+	settings := map[string]interface{}{"requiredApprovers": float64(1), "requiredSuccessfulBuilds": float64(1)}
+	if got := fake.requests["POST /rest/api/1.0/projects/ADOBE/repos/awesome-project/settings/pull-requests"]; !reflect.DeepEqual(got, settings) {
+		t.Errorf("Pull request settings do not match: got %#v, want %#v", got, settings)
+	}
+
+	var restrictions int
+	for _, key := range fake.order {
+		if key == "POST /rest/branch-permissions/2.0/projects/ADOBE/repos/awesome-project/restrictions" {
+			restrictions++
+		}
+	}
+
+	// no-deletes, fast-forward-only, pull-request-only and read-only
+	if restrictions != 4 {
+		t.Errorf("Number of restrictions does not match: got %v, want %v", restrictions, 4)
+	}
+
+	last := fake.requests["POST /rest/branch-permissions/2.0/projects/ADOBE/repos/awesome-project/restrictions"].(map[string]interface{})
+	if last["type"] != "read-only" || !reflect.DeepEqual(last["groups"], []interface{}{"developers"}) {
+		t.Errorf("Restriction does not match: got %#v", last)
+	}
+}
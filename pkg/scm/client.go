@@ -0,0 +1,128 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package scm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Error returned by REST API of the provider
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("server responded with %v: %v", e.StatusCode, e.Message)
+}
+
+// client of REST API authenticated with Authorization header
+type client struct {
+	base          string
+	authorization string
+	http          *http.Client
+}
+
+func newClient(base, authorization string) *client {
+	return &client{
+		base:          strings.TrimSuffix(base, "/"),
+		authorization: authorization,
+		http:          &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// do sends request and decodes response into out (when it's not nil), 401 response is returned as ErrUnauthorized
+func (c *client) do(method, path string, body, out interface{}) error {
+	_, err := c.request(method, path, body, out)
+	return err
+}
+
+// request works like do, but also returns headers of the response
+func (c *client) request(method, path string, body, out interface{}) (http.Header, error) {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequest(method, c.base+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", c.authorization)
+	req.Header.Set("Accept", "application/json")
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return resp.Header, ErrUnauthorized
+	}
+
+	if resp.StatusCode >= 300 {
+		// Gitea returns message, Bitbucket Server returns list of errors, GitLab returns message (string or
+		// validation errors of fields) or error
+		var e struct {
+			Message json.RawMessage `json:"message"`
+			Error   string          `json:"error"`
+			Errors  []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+
+		_ = json.NewDecoder(resp.Body).Decode(&e)
+
+		msg := e.Error
+		if len(e.Message) > 0 {
+			var s string
+			if json.Unmarshal(e.Message, &s) == nil {
+				msg = s
+			} else {
+				msg = string(e.Message)
+			}
+		}
+
+		for _, err := range e.Errors {
+			msg = strings.TrimSpace(msg + " " + err.Message)
+		}
+
+		return resp.Header, &Error{StatusCode: resp.StatusCode, Message: msg}
+	}
+
+	if out == nil {
+		return resp.Header, nil
+	}
+
+	return resp.Header, json.NewDecoder(resp.Body).Decode(out)
+}
+
+// isStatus checks if err is API error with a given status code
+func isStatus(err error, code int) bool {
+	e, ok := err.(*Error)
+	return ok && e.StatusCode == code
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package scm
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+// response of the fake server
+type response struct {
+	status int
+	body   string
+	header map[string]string
+}
+
+// fakeServer replies with canned responses by "METHOD /path?query" and records request bodies
+type fakeServer struct {
+	mu        sync.Mutex
+	responses map[string]response
+	requests  map[string]interface{}
+	order     []string
+}
+
+func newFakeServer(t *testing.T, auth string, responses map[string]response) (*fakeServer, *httptest.Server, *url.URL) {
+	f := &fakeServer{responses: responses, requests: map[string]interface{}{}}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		if r.Header.Get("Authorization") != auth {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		key := r.Method + " " + r.URL.EscapedPath()
+		if r.URL.RawQuery != "" {
+			key += "?" + r.URL.RawQuery
+		}
+
+		var body interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		f.requests[key] = body
+		f.order = append(f.order, key)
+
+		resp, ok := f.responses[key]
+		if !ok {
+			resp = response{status: http.StatusNotFound, body: `{"message":"not found"}`}
+		}
+
+		for k, v := range resp.header {
+			w.Header().Set(k, v)
+		}
+
+		if resp.status != 0 {
+			w.WriteHeader(resp.status)
+		}
+
+		_, _ = w.Write([]byte(resp.body))
+	}))
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return f, server, u
+}
+
+func TestClientErrors(t *testing.T) {
+	_, server, u := newFakeServer(t, "token", map[string]response{
+		"GET /gitea":     {status: http.StatusConflict, body: `{"message":"repository already exists"}`},
+		"GET /bitbucket": {status: http.StatusBadRequest, body: `{"errors":[{"message":"first"},{"message":"second"}]}`},
+		"GET /gitlab":    {status: http.StatusBadRequest, body: `{"message":{"path":["has already been taken"]}}`},
+		"GET /error":     {status: http.StatusNotFound, body: `{"error":"404 Not Found"}`},
+	})
+
+	defer server.Close()
+
+	c := newClient(u.String(), "token")
+
+	tests := []struct {
+		path    string
+		status  int
+		message string
+	}{
+		{"/gitea", http.StatusConflict, "repository already exists"},
+		{"/bitbucket", http.StatusBadRequest, "first second"},
+		{"/gitlab", http.StatusBadRequest, `{"path":["has already been taken"]}`},
+		{"/error", http.StatusNotFound, "404 Not Found"},
+		{"/missing", http.StatusNotFound, "not found"},
+	}
+
+	for _, test := range tests {
+		err := c.do("GET", test.path, nil, nil)
+
+		e, ok := err.(*Error)
+		if !ok || e.StatusCode != test.status || e.Message != test.message {
+			t.Errorf("Error of %v does not match: got %#v, want %v %#v", test.path, err, test.status, test.message)
+		}
+	}
+
+	if err := newClient(u.String(), "invalid").do("GET", "/gitea", nil, nil); err != ErrUnauthorized {
+		t.Errorf("Error does not match: got %v, want %v", err, ErrUnauthorized)
+	}
+}
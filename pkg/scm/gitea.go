@@ -0,0 +1,139 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package scm
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Gitea provider, owner is organisation or user. Code owner reviews and enforcement for admins are not supported.
+type Gitea struct {
+	client *client
+	login  string
+}
+
+// NewGitea builds provider for Gitea at a given URL authenticated with access token
+func NewGitea(u *url.URL, token string) *Gitea {
+	return &Gitea{client: newClient(u.String()+"/api/v1", "token "+token)}
+}
+
+type giteaRepository struct {
+	HTMLURL  string `json:"html_url"`
+	CloneURL string `json:"clone_url"`
+}
+
+// User returns login of authenticated user
+func (g *Gitea) User() (string, error) {
+	if g.login != "" {
+		return g.login, nil
+	}
+
+	var user struct {
+		Login string `json:"login"`
+	}
+
+	if err := g.client.do("GET", "/user", nil, &user); err != nil {
+		return "", err
+	}
+
+	g.login = user.Login
+	return g.login, nil
+}
+
+// CreateRepository under organisation or current user
+func (g *Gitea) CreateRepository(owner, name string, private bool) (*Repository, error) {
+	login, err := g.User()
+	if err != nil {
+		return nil, err
+	}
+
+	path := "/org/" + url.PathEscape(owner) + "/repos"
+	if strings.EqualFold(owner, login) {
+		path = "/user/repos"
+	}
+
+	repo := new(giteaRepository)
+
+	err = g.client.do("POST", path, map[string]interface{}{"name": name, "private": private}, repo)
+	if isStatus(err, http.StatusConflict) {
+		return nil, ErrExists
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Repository{Owner: owner, Name: name, Slug: name, WebURL: repo.HTMLURL, CloneURL: repo.CloneURL}, nil
+}
+
+// AddCollaborator to the repository
+func (g *Gitea) AddCollaborator(owner, name, user string, perm Permission) error {
+	return g.client.do("PUT", g.repo(owner, name)+"/collaborators/"+url.PathEscape(user), map[string]string{
+		"permission": string(perm),
+	}, nil)
+}
+
+// AddDeployKey to the repository
+func (g *Gitea) AddDeployKey(owner, name, title, key string, readOnly bool) error {
+	return g.client.do("POST", g.repo(owner, name)+"/keys", map[string]interface{}{
+		"title":     title,
+		"key":       key,
+		"read_only": readOnly,
+	}, nil)
+}
+
+// SetSecret creates or updates Gitea Actions secret
+func (g *Gitea) SetSecret(owner, name, key, value string) error {
+	return g.client.do("PUT", g.repo(owner, name)+"/actions/secrets/"+url.PathEscape(key), map[string]string{
+		"data": value,
+	}, nil)
+}
+
+// ProtectBranch of the repository, existing protection of the branch is updated
+func (g *Gitea) ProtectBranch(owner, name, branch string, p Protection) error {
+	body := map[string]interface{}{
+		"branch_name":              branch,
+		"rule_name":                branch,
+		"required_approvals":       p.RequiredReviews,
+		"dismiss_stale_approvals":  p.DismissStaleReviews,
+		"enable_status_check":      len(p.RequiredStatusChecks) > 0,
+		"status_check_contexts":    append([]string{}, p.RequiredStatusChecks...),
+		"block_on_outdated_branch": p.StrictStatusChecks,
+		"enable_push":              true,
+	}
+
+	if len(p.RestrictPushUsers) > 0 || len(p.RestrictPushTeams) > 0 {
+		body["enable_push_whitelist"] = true
+		body["push_whitelist_usernames"] = append([]string{}, p.RestrictPushUsers...)
+		body["push_whitelist_teams"] = append([]string{}, p.RestrictPushTeams...)
+	}
+
+	path := g.repo(owner, name) + "/branch_protections"
+
+	err := g.client.do("GET", path+"/"+url.PathEscape(branch), nil, nil)
+	if isStatus(err, http.StatusNotFound) {
+		return g.client.do("POST", path, body, nil)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return g.client.do("PATCH", path+"/"+url.PathEscape(branch), body, nil)
+}
+
+func (g *Gitea) repo(owner, name string) string {
+	return fmt.Sprintf("/repos/%v/%v", url.PathEscape(owner), url.PathEscape(name))
+}
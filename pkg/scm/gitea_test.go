@@ -0,0 +1,98 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package scm
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGitea(t *testing.T) {
+	fake, server, u := newFakeServer(t, "token secret", map[string]response{
+		"GET /api/v1/user":                                          {body: `{"login":"octocat"}`},
+		"POST /api/v1/org/adobe/repos":                              {status: http.StatusCreated, body: `{"html_url":"https://gitea.example.com/adobe/awesome","clone_url":"https://gitea.example.com/adobe/awesome.git"}`},
+		"POST /api/v1/user/repos":                                   {status: http.StatusConflict, body: `{"message":"The repository with the same name already exists."}`},
+		"PUT /api/v1/repos/adobe/awesome/collaborators/hubot":       {status: http.StatusNoContent},
+		"POST /api/v1/repos/adobe/awesome/keys":                     {status: http.StatusCreated, body: `{}`},
+		"PUT /api/v1/repos/adobe/awesome/actions/secrets/TOKEN":     {status: http.StatusCreated},
+		"GET /api/v1/repos/adobe/awesome/branch_protections/main":   {body: `{}`},
+		"PATCH /api/v1/repos/adobe/awesome/branch_protections/main": {body: `{}`},
+		"POST /api/v1/repos/adobe/awesome/branch_protections":       {status: http.StatusCreated, body: `{}`},
+	})
+
+	defer server.Close()
+
+	g := NewGitea(u, "secret")
+
+	repo, err := g.CreateRepository("adobe", "awesome", true)
+	if err != nil {
+		t.Fatalf("CreateRepository() failed: %v", err)
+	}
+
+	want := &Repository{Owner: "adobe", Name: "awesome", Slug: "awesome", WebURL: "https://gitea.example.com/adobe/awesome", CloneURL: "https://gitea.example.com/adobe/awesome.git"}
+	if !reflect.DeepEqual(repo, want) {
+		t.Errorf("Repository does not match: got %#v, want %#v", repo, want)
+	}
+
+	// owner is compared with user login case-insensitively
+	if _, err := g.CreateRepository("OctoCat", "awesome", true); err != ErrExists {
+		t.Errorf("CreateRepository() error does not match: got %v, want %v", err, ErrExists)
+	}
+
+	if err := g.AddCollaborator("adobe", "awesome", "hubot", Write); err != nil {
+		t.Fatalf("AddCollaborator() failed: %v", err)
+	}
+
+	if err := g.AddDeployKey("adobe", "awesome", "Deploy Key", "ssh-rsa AAAA", true); err != nil {
+		t.Fatalf("AddDeployKey() failed: %v", err)
+	}
+
+	if err := g.SetSecret("adobe", "awesome", "TOKEN", "value"); err != nil {
+		t.Fatalf("SetSecret() failed: %v", err)
+	}
+
+	p := Protection{RequiredReviews: 2, RequiredStatusChecks: []string{"ci"}, RestrictPushUsers: []string{"octocat"}}
+	if err := g.ProtectBranch("adobe", "awesome", "main", p); err != nil {
+		t.Fatalf("ProtectBranch() failed: %v", err)
+	}
+
+	if err := g.ProtectBranch("adobe", "awesome", "develop", p); err != nil {
+		t.Fatalf("ProtectBranch() failed: %v", err)
+	}
+
+	requests := map[string]interface{}{
+		"POST /api/v1/org/adobe/repos":                          map[string]interface{}{"name": "awesome", "private": true},
+		"PUT /api/v1/repos/adobe/awesome/collaborators/hubot":   map[string]interface{}{"permission": "write"},
+		"POST /api/v1/repos/adobe/awesome/keys":                 map[string]interface{}{"title": "Deploy Key", "key": "ssh-rsa AAAA", "read_only": true},
+		"PUT /api/v1/repos/adobe/awesome/actions/secrets/TOKEN": map[string]interface{}{"data": "value"},
+	}
+
+	for key, want := range requests {
+		if got := fake.requests[key]; !reflect.DeepEqual(got, want) {
+			t.Errorf("Request %v does not match: got %#v, want %#v", key, got, want)
+		}
+	}
+
+	protection := fake.requests["PATCH /api/v1/repos/adobe/awesome/branch_protections/main"].(map[string]interface{})
+	if protection["required_approvals"] != float64(2) || protection["enable_push_whitelist"] != true {
+		t.Errorf("Protection does not match: got %#v", protection)
+	}
+
+	if created := fake.requests["POST /api/v1/repos/adobe/awesome/branch_protections"].(map[string]interface{}); created["branch_name"] != "develop" {
+		t.Errorf("Protection of new branch does not match: got %#v", created)
+	}
+
+	if _, err := NewGitea(u, "invalid").User(); err != ErrUnauthorized {
+		t.Errorf("User() error does not match: got %v, want %v", err, ErrUnauthorized)
+	}
+}
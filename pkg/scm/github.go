@@ -0,0 +1,210 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package scm
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"github.com/google/go-github/github"
+	"golang.org/x/crypto/nacl/box"
+	"net/http"
+	"strings"
+)
+
+// GitHub provider, owner is organisation or user
+type GitHub struct {
+	client *github.Client
+	login  string
+}
+
+// NewGitHub builds provider using authenticated client (see auth.NewGitHubClient)
+func NewGitHub(client *github.Client) *GitHub {
+	return &GitHub{client: client}
+}
+
+// User returns login of authenticated user
+func (g *GitHub) User() (string, error) {
+	if g.login != "" {
+		return g.login, nil
+	}
+
+	user, _, err := g.client.Users.Get(context.Background(), "")
+	if e, ok := err.(*github.ErrorResponse); ok && e.Response != nil && e.Response.StatusCode == http.StatusUnauthorized {
+		return "", ErrUnauthorized
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	g.login = user.GetLogin()
+	return g.login, nil
+}
+
+// CreateRepository under organisation or current user
+func (g *GitHub) CreateRepository(owner, name string, private bool) (*Repository, error) {
+	// GitHub API requires org to be empty when creating repository under "current" account. Login of GitHub App
+	// installation is unknown, its repositories are always created in organisation.
+	login, err := g.User()
+	if err == ErrUnauthorized {
+		return nil, err
+	}
+
+	org := owner
+	if err == nil && strings.EqualFold(org, login) {
+		org = ""
+	}
+
+	repo, _, err := g.client.Repositories.Create(context.Background(), org, &github.Repository{
+		Name:    github.String(name),
+		Private: github.Bool(private),
+	})
+
+	if err != nil {
+		if strings.Contains(err.Error(), "name already exists on this account") {
+			return nil, ErrExists
+		}
+
+		return nil, err
+	}
+
+	return &Repository{Owner: owner, Name: name, Slug: name, WebURL: repo.GetHTMLURL(), CloneURL: repo.GetCloneURL()}, nil
+}
+
+// githubPermissions maps permissions to GitHub ones
+var githubPermissions = map[Permission]string{Read: "pull", Write: "push", Admin: "admin"}
+
+// AddCollaborator to the repository
+func (g *GitHub) AddCollaborator(owner, name, user string, perm Permission) error {
+	p, ok := githubPermissions[perm]
+	if !ok {
+		p = string(perm)
+	}
+
+	_, err := g.client.Repositories.AddCollaborator(context.Background(), owner, name, user, &github.RepositoryAddCollaboratorOptions{
+		Permission: p,
+	})
+
+	return err
+}
+
+// AddDeployKey to the repository
+func (g *GitHub) AddDeployKey(owner, name, title, key string, readOnly bool) error {
+	_, _, err := g.client.Repositories.CreateKey(context.Background(), owner, name, &github.Key{
+		Title:    github.String(title),
+		Key:      github.String(key),
+		ReadOnly: github.Bool(readOnly),
+	})
+
+	return err
+}
+
+// githubPublicKey is used to encrypt Actions secrets of the repository
+type githubPublicKey struct {
+	KeyID string `json:"key_id"`
+	Key   string `json:"key"`
+}
+
+// SetSecret creates or updates GitHub Actions secret, value is encrypted with public key of the repository (libsodium
+// sealed box) before it's sent to GitHub
+func (g *GitHub) SetSecret(owner, name, key, value string) error {
+	// go-github has no Actions API, so requests are built manually
+	ctx := context.Background()
+
+	req, err := g.client.NewRequest("GET", fmt.Sprintf("repos/%v/%v/actions/secrets/public-key", owner, name), nil)
+	if err != nil {
+		return err
+	}
+
+	public := new(githubPublicKey)
+	if _, err := g.client.Do(ctx, req, public); err != nil {
+		return fmt.Errorf("unable to read public key of the repository: %v", err)
+	}
+
+	encrypted, err := sealSecret(public.Key, value)
+	if err != nil {
+		return err
+	}
+
+	req, err = g.client.NewRequest("PUT", fmt.Sprintf("repos/%v/%v/actions/secrets/%v", owner, name, key), map[string]string{
+		"encrypted_value": encrypted,
+		"key_id":          public.KeyID,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = g.client.Do(ctx, req, nil)
+	return err
+}
+
+// sealSecret encrypts value with base64 encoded Curve25519 public key, returns base64 encoded sealed box
+func sealSecret(publicKey, value string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(data) != 32 {
+		return "", fmt.Errorf("invalid public key of the repository %#v", publicKey)
+	}
+
+	var recipient [32]byte
+	copy(recipient[:], data)
+
+	sealed, err := box.SealAnonymous(nil, []byte(value), &recipient, rand.Reader)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// ProtectBranch of the repository
+func (g *GitHub) ProtectBranch(owner, name, branch string, p Protection) error {
+	_, _, err := g.client.Repositories.UpdateBranchProtection(context.Background(), owner, name, branch, GitHubProtection(p))
+	return err
+}
+
+// GitHubProtection builds request to branch protection API
+func GitHubProtection(p Protection) *github.ProtectionRequest {
+	r := &github.ProtectionRequest{EnforceAdmins: p.EnforceAdmins}
+
+	if len(p.RequiredStatusChecks) > 0 || p.StrictStatusChecks {
+		r.RequiredStatusChecks = &github.RequiredStatusChecks{
+			Strict:   p.StrictStatusChecks,
+			Contexts: append([]string{}, p.RequiredStatusChecks...),
+		}
+	}
+
+	if p.RequiredReviews > 0 || p.DismissStaleReviews || p.RequireCodeOwnerReviews {
+		// GitHub requires at least one approving review when reviews are required
+		count := p.RequiredReviews
+		if count < 1 {
+			count = 1
+		}
+
+		r.RequiredPullRequestReviews = &github.PullRequestReviewsEnforcementRequest{
+			DismissStaleReviews:          p.DismissStaleReviews,
+			RequireCodeOwnerReviews:      p.RequireCodeOwnerReviews,
+			RequiredApprovingReviewCount: count,
+		}
+	}
+
+	if len(p.RestrictPushUsers) > 0 || len(p.RestrictPushTeams) > 0 {
+		r.Restrictions = &github.BranchRestrictionsRequest{
+			Users: append([]string{}, p.RestrictPushUsers...),
+			Teams: append([]string{}, p.RestrictPushTeams...),
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package scm
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"github.com/adobe/go-starter/pkg/auth"
+	"github.com/google/go-github/github"
+	"golang.org/x/crypto/nacl/box"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGitHub(t *testing.T) {
+	public, private, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake, server, u := newFakeServer(t, "Bearer secret", map[string]response{
+		"GET /api/v3/user":                                             {body: `{"login":"octocat"}`},
+		"POST /api/v3/user/repos":                                      {status: http.StatusCreated, body: `{"html_url":"https://github.example.com/octocat/awesome","clone_url":"https://github.example.com/octocat/awesome.git"}`},
+		"POST /api/v3/orgs/adobe/repos":                                {status: http.StatusUnprocessableEntity, body: `{"message":"Repository creation failed.","errors":[{"resource":"Repository","code":"custom","field":"name","message":"name already exists on this account"}]}`},
+		"PUT /api/v3/repos/octocat/awesome/collaborators/hubot":        {status: http.StatusNoContent},
+		"GET /api/v3/repos/octocat/awesome/actions/secrets/public-key": {body: `{"key_id":"1234","key":"` + base64.StdEncoding.EncodeToString(public[:]) + `"}`},
+		"PUT /api/v3/repos/octocat/awesome/actions/secrets/TOKEN":      {status: http.StatusCreated},
+	})
+
+	defer server.Close()
+
+	cli, err := auth.NewGitHubClient(u.String(), "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGitHub(cli)
+
+	repo, err := g.CreateRepository("octocat", "awesome", true)
+	if err != nil {
+		t.Fatalf("CreateRepository() failed: %v", err)
+	}
+
+	if repo.CloneURL != "https://github.example.com/octocat/awesome.git" {
+		t.Errorf("Clone URL does not match: got %#v", repo.CloneURL)
+	}
+
+	if want := map[string]interface{}{"name": "awesome", "private": true}; !reflect.DeepEqual(fake.requests["POST /api/v3/user/repos"], want) {
+		t.Errorf("Request does not match: got %#v, want %#v", fake.requests["POST /api/v3/user/repos"], want)
+	}
+
+	if _, err := g.CreateRepository("adobe", "awesome", true); err != ErrExists {
+		t.Errorf("CreateRepository() error does not match: got %v, want %v", err, ErrExists)
+	}
+
+	if err := g.AddCollaborator("octocat", "awesome", "hubot", Read); err != nil {
+		t.Fatalf("AddCollaborator() failed: %v", err)
+	}
+
+	if want := map[string]interface{}{"permission": "pull"}; !reflect.DeepEqual(fake.requests["PUT /api/v3/repos/octocat/awesome/collaborators/hubot"], want) {
+		t.Errorf("Request does not match: got %#v, want %#v", fake.requests["PUT /api/v3/repos/octocat/awesome/collaborators/hubot"], want)
+	}
+
+	if err := g.SetSecret("octocat", "awesome", "TOKEN", "value"); err != nil {
+		t.Fatalf("SetSecret() failed: %v", err)
+	}
+
+	secret := fake.requests["PUT /api/v3/repos/octocat/awesome/actions/secrets/TOKEN"].(map[string]interface{})
+
+	sealed, _ := base64.StdEncoding.DecodeString(secret["encrypted_value"].(string))
+	if value, ok := box.OpenAnonymous(nil, sealed, public, private); !ok || string(value) != "value" || secret["key_id"] != "1234" {
+		t.Errorf("Secret does not match: got %#v", secret)
+	}
+
+	invalid, _ := auth.NewGitHubClient(u.String(), "invalid")
+	if _, err := NewGitHub(invalid).User(); err != ErrUnauthorized {
+		t.Errorf("User() error does not match: got %v, want %v", err, ErrUnauthorized)
+	}
+}
+
+func TestGitHubProtection(t *testing.T) {
+	tests := []struct {
+		protection Protection
+		want       *github.ProtectionRequest
+	}{
+		{Protection{}, &github.ProtectionRequest{}},
+		{
+			Protection{RequiredStatusChecks: []string{"ci"}, EnforceAdmins: true},
+			&github.ProtectionRequest{EnforceAdmins: true, RequiredStatusChecks: &github.RequiredStatusChecks{Contexts: []string{"ci"}}},
+		},
+		{
+			Protection{DismissStaleReviews: true},
+			&github.ProtectionRequest{RequiredPullRequestReviews: &github.PullRequestReviewsEnforcementRequest{DismissStaleReviews: true, RequiredApprovingReviewCount: 1}},
+		},
+	}
+
+	for _, test := range tests {
+		if got := GitHubProtection(test.protection); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Request of %#v does not match: got %#v, want %#v", test.protection, got, test.want)
+		}
+
+		if test.protection.Enabled() != (test.protection.EnforceAdmins || test.protection.DismissStaleReviews) {
+			t.Errorf("Enabled() of %#v does not match", test.protection)
+		}
+	}
+}
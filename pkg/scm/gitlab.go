@@ -0,0 +1,133 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package scm
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GitLab client of v4 API. Projects live in namespaces (users or nested groups) and are referenced by their IDs,
+// so it doesn't implement Provider.
+type GitLab struct {
+	client *client
+}
+
+// NewGitLab builds client for GitLab at a given URL authenticated with personal token
+func NewGitLab(u *url.URL, token string) *GitLab {
+	return &GitLab{client: newClient(u.String()+"/api/v4", "Bearer "+token)}
+}
+
+// GitLabNamespace is a user or a group
+type GitLabNamespace struct {
+	ID       int    `json:"id"`
+	FullPath string `json:"full_path"`
+}
+
+// GitLabProject is a repository of GitLab
+type GitLabProject struct {
+	ID                int    `json:"id"`
+	WebURL            string `json:"web_url"`
+	HTTPURLToRepo     string `json:"http_url_to_repo"`
+	PathWithNamespace string `json:"path_with_namespace"`
+}
+
+// Namespace returns user or group by its full path (eq. group/subgroup)
+func (g *GitLab) Namespace(path string) (*GitLabNamespace, error) {
+	ns := new(GitLabNamespace)
+	return ns, g.client.do("GET", "/namespaces/"+url.PathEscape(path), nil, ns)
+}
+
+// CreateProject in a given namespace, visibility is private, internal or public. ErrExists is returned when project
+// already exists.
+func (g *GitLab) CreateProject(namespace int, name, visibility, description string) (*GitLabProject, error) {
+	p := new(GitLabProject)
+
+	body := map[string]interface{}{
+		"name":         name,
+		"path":         name,
+		"namespace_id": namespace,
+		"visibility":   visibility,
+	}
+
+	if description != "" {
+		body["description"] = description
+	}
+
+	err := g.client.do("POST", "/projects", body, p)
+	if isTaken(err) {
+		return nil, ErrExists
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// UserID returns ID of user by username
+func (g *GitLab) UserID(username string) (int, error) {
+	var users []struct {
+		ID int `json:"id"`
+	}
+
+	if err := g.client.do("GET", "/users?username="+url.QueryEscape(username), nil, &users); err != nil {
+		return 0, err
+	}
+
+	if len(users) == 0 {
+		return 0, fmt.Errorf("user %#v is not found", username)
+	}
+
+	return users[0].ID, nil
+}
+
+// AddMember adds user to the project with a given access level
+func (g *GitLab) AddMember(project, user, level int) error {
+	return g.client.do("POST", fmt.Sprintf("/projects/%v/members", project), map[string]int{
+		"user_id":      user,
+		"access_level": level,
+	}, nil)
+}
+
+// AddDeployKey adds SSH deployment key to the project
+func (g *GitLab) AddDeployKey(project int, title, key string, canPush bool) error {
+	return g.client.do("POST", fmt.Sprintf("/projects/%v/deploy_keys", project), map[string]interface{}{
+		"title":    title,
+		"key":      key,
+		"can_push": canPush,
+	}, nil)
+}
+
+// SetVariable creates or updates CI/CD variable of the project
+func (g *GitLab) SetVariable(project int, key, value string, masked bool) error {
+	body := map[string]interface{}{
+		"key":    key,
+		"value":  value,
+		"masked": masked,
+	}
+
+	err := g.client.do("POST", fmt.Sprintf("/projects/%v/variables", project), body, nil)
+	if isTaken(err) {
+		err = g.client.do("PUT", fmt.Sprintf("/projects/%v/variables/%v", project, url.PathEscape(key)), body, nil)
+	}
+
+	return err
+}
+
+// isTaken checks if err is GitLab validation error of a name which is already used
+func isTaken(err error) bool {
+	return isStatus(err, http.StatusBadRequest) && strings.Contains(err.Error(), "has already been taken")
+}
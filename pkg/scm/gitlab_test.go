@@ -0,0 +1,120 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package scm
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGitLab(t *testing.T) {
+	fake, server, u := newFakeServer(t, "Bearer secret", map[string]response{
+		"GET /api/v4/namespaces/adobe%2Fservices": {body: `{"id":7,"full_path":"adobe/services"}`},
+		"POST /api/v4/projects":                   {status: http.StatusCreated, body: `{"id":42,"web_url":"https://gitlab.example.com/adobe/services/awesome","http_url_to_repo":"https://gitlab.example.com/adobe/services/awesome.git","path_with_namespace":"adobe/services/awesome"}`},
+		"GET /api/v4/users?username=octocat":      {body: `[{"id":5,"username":"octocat"}]`},
+		"GET /api/v4/users?username=missing":      {body: `[]`},
+		"POST /api/v4/projects/42/members":        {status: http.StatusCreated, body: `{}`},
+		"POST /api/v4/projects/42/deploy_keys":    {status: http.StatusCreated, body: `{}`},
+		"POST /api/v4/projects/42/variables":      {status: http.StatusBadRequest, body: `{"message":{"key":["(TOKEN) has already been taken"]}}`},
+		"PUT /api/v4/projects/42/variables/TOKEN": {body: `{}`},
+		"POST /api/v4/projects/43/deploy_keys":    {status: http.StatusBadRequest, body: `{"message":{"key":["is invalid"]}}`},
+	})
+
+	defer server.Close()
+
+	g := NewGitLab(u, "secret")
+
+	ns, err := g.Namespace("adobe/services")
+	if err != nil {
+		t.Fatalf("Namespace() failed: %v", err)
+	}
+
+	if want := (&GitLabNamespace{ID: 7, FullPath: "adobe/services"}); !reflect.DeepEqual(ns, want) {
+		t.Errorf("Namespace does not match: got %#v, want %#v", ns, want)
+	}
+
+	if _, err := g.Namespace("missing"); !isStatus(err, http.StatusNotFound) {
+		t.Errorf("Namespace() error does not match: got %v, want %v", err, http.StatusNotFound)
+	}
+
+	project, err := g.CreateProject(ns.ID, "awesome", "private", "Awesome project")
+	if err != nil {
+		t.Fatalf("CreateProject() failed: %v", err)
+	}
+
+	want := &GitLabProject{
+		ID:                42,
+		WebURL:            "https://gitlab.example.com/adobe/services/awesome",
+		HTTPURLToRepo:     "https://gitlab.example.com/adobe/services/awesome.git",
+		PathWithNamespace: "adobe/services/awesome",
+	}
+
+	if !reflect.DeepEqual(project, want) {
+		t.Errorf("Project does not match: got %#v, want %#v", project, want)
+	}
+
+	id, err := g.UserID("octocat")
+	if err != nil {
+		t.Fatalf("UserID() failed: %v", err)
+	}
+
+	if _, err := g.UserID("missing"); err == nil {
+		t.Errorf("UserID() of missing user must fail")
+	}
+
+	if err := g.AddMember(project.ID, id, 40); err != nil {
+		t.Fatalf("AddMember() failed: %v", err)
+	}
+
+	if err := g.AddDeployKey(project.ID, "Deploy Key", "ssh-rsa AAAA", true); err != nil {
+		t.Fatalf("AddDeployKey() failed: %v", err)
+	}
+
+	if err := g.AddDeployKey(43, "Deploy Key", "invalid", false); !isStatus(err, http.StatusBadRequest) {
+		t.Errorf("AddDeployKey() error does not match: got %v, want %v", err, http.StatusBadRequest)
+	}
+
+	// existing variable is updated
+	if err := g.SetVariable(project.ID, "TOKEN", "value", true); err != nil {
+		t.Fatalf("SetVariable() failed: %v", err)
+	}
+
+	requests := map[string]interface{}{
+		"POST /api/v4/projects":                   map[string]interface{}{"name": "awesome", "path": "awesome", "namespace_id": float64(7), "visibility": "private", "description": "Awesome project"},
+		"POST /api/v4/projects/42/members":        map[string]interface{}{"user_id": float64(5), "access_level": float64(40)},
+		"POST /api/v4/projects/42/deploy_keys":    map[string]interface{}{"title": "Deploy Key", "key": "ssh-rsa AAAA", "can_push": true},
+		"PUT /api/v4/projects/42/variables/TOKEN": map[string]interface{}{"key": "TOKEN", "value": "value", "masked": true},
+	}
+
+	for key, want := range requests {
+		if got := fake.requests[key]; !reflect.DeepEqual(got, want) {
+			t.Errorf("Request %v does not match: got %#v, want %#v", key, got, want)
+		}
+	}
+
+	if _, err := NewGitLab(u, "invalid").Namespace("adobe/services"); err != ErrUnauthorized {
+		t.Errorf("Namespace() error does not match: got %v, want %v", err, ErrUnauthorized)
+	}
+}
+
+func TestGitLabExists(t *testing.T) {
+	_, server, u := newFakeServer(t, "Bearer secret", map[string]response{
+		"POST /api/v4/projects": {status: http.StatusBadRequest, body: `{"message":{"name":["has already been taken"],"path":["has already been taken"]}}`},
+	})
+
+	defer server.Close()
+
+	if _, err := NewGitLab(u, "secret").CreateProject(7, "awesome", "private", ""); err != ErrExists {
+		t.Errorf("CreateProject() error does not match: got %v, want %v", err, ErrExists)
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+package scm
+
+// Protection rules of a branch, rules which are not supported by provider are ignored
+type Protection struct {
+	RequiredReviews         int      `yaml:"required_reviews"`
+	DismissStaleReviews     bool     `yaml:"dismiss_stale_reviews"`
+	RequireCodeOwnerReviews bool     `yaml:"require_code_owner_reviews"`
+	RequiredStatusChecks    []string `yaml:"required_status_checks"`
+	StrictStatusChecks      bool     `yaml:"strict_status_checks"`
+	EnforceAdmins           bool     `yaml:"enforce_admins"`
+	RestrictPushUsers       []string `yaml:"restrict_push_users"`
+	RestrictPushTeams       []string `yaml:"restrict_push_teams"`
+}
+
+// Merge rules set with command line flags into rules from config file, flags take precedence
+func (p *Protection) Merge(flags Protection) {
+	if flags.RequiredReviews != 0 {
+		p.RequiredReviews = flags.RequiredReviews
+	}
+
+	p.DismissStaleReviews = p.DismissStaleReviews || flags.DismissStaleReviews
+	p.RequireCodeOwnerReviews = p.RequireCodeOwnerReviews || flags.RequireCodeOwnerReviews
+	p.StrictStatusChecks = p.StrictStatusChecks || flags.StrictStatusChecks
+	p.EnforceAdmins = p.EnforceAdmins || flags.EnforceAdmins
+
+	if len(flags.RequiredStatusChecks) > 0 {
+		p.RequiredStatusChecks = flags.RequiredStatusChecks
+	}
+
+	if len(flags.RestrictPushUsers) > 0 {
+		p.RestrictPushUsers = flags.RestrictPushUsers
+	}
+
+	if len(flags.RestrictPushTeams) > 0 {
+		p.RestrictPushTeams = flags.RestrictPushTeams
+	}
+}
+
+// Enabled returns true if any rule is set
+func (p Protection) Enabled() bool {
+	return p.RequiredReviews > 0 || p.DismissStaleReviews || p.RequireCodeOwnerReviews || p.StrictStatusChecks ||
+		p.EnforceAdmins || len(p.RequiredStatusChecks) > 0 || len(p.RestrictPushUsers) > 0 || len(p.RestrictPushTeams) > 0
+}
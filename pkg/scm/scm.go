@@ -0,0 +1,61 @@
+/*
+Copyright 2019 Adobe
+All Rights Reserved.
+
+NOTICE: Adobe permits you to use, modify, and distribute this file in
+accordance with the terms of the Adobe license agreement accompanying
+it. If you have received this file from a source other than Adobe,
+then your use, modification, or distribution of it requires the prior
+written permission of Adobe.
+*/
+
+// Package scm provisions repositories in source code management systems (GitHub, Gitea, Bitbucket Server, GitLab)
+package scm
+
+import (
+	"errors"
+)
+
+var (
+	// ErrExists is returned when repository already exists
+	ErrExists = errors.New("repository already exists")
+	// ErrUnauthorized is returned when provider does not accept the token
+	ErrUnauthorized = errors.New("token is rejected")
+	// ErrUnsupported is returned when provider has no such feature
+	ErrUnsupported = errors.New("not supported by the provider")
+)
+
+// Permission of collaborator
+type Permission string
+
+const (
+	Read  Permission = "read"
+	Write Permission = "write"
+	Admin Permission = "admin"
+)
+
+// Repository created by provider
+type Repository struct {
+	Owner string
+	Name  string
+	// Slug references the repository in later calls, it's the name unless provider rewrites it
+	Slug string
+	// WebURL is a page of the repository
+	WebURL string
+	// CloneURL is HTTPS URL used by git
+	CloneURL string
+}
+
+// Provider of repositories. Owner is organisation, user or project depending on provider, repositories are referenced
+// by Repository.Slug returned when they are created.
+type Provider interface {
+	// User returns login of authenticated user, ErrUnauthorized is returned when token is rejected
+	User() (string, error)
+	// CreateRepository returns ErrExists when repository already exists
+	CreateRepository(owner, name string, private bool) (*Repository, error)
+	AddCollaborator(owner, name, user string, perm Permission) error
+	AddDeployKey(owner, name, title, key string, readOnly bool) error
+	// SetSecret of CI pipelines, value is encrypted when provider requires it
+	SetSecret(owner, name, key, value string) error
+	ProtectBranch(owner, name, branch string, p Protection) error
+}